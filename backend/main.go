@@ -2,20 +2,176 @@ package main
 
 import (
 	"flag"
+	"fmt"
+	"io"
 	"log"
+	"os"
 
 	"github.com/fallra1n/humanity/src"
+	"github.com/fallra1n/humanity/src/config"
 )
 
 func main() {
+	// Подкоманда проверки конфигурации: humanity lint
+	if len(os.Args) > 1 && os.Args[1] == "lint" {
+		os.Exit(lint(os.Args[2:]))
+	}
+	// Граф целей и действий: humanity graph
+	if len(os.Args) > 1 && os.Args[1] == "graph" {
+		graph(os.Args[2:])
+		return
+	}
+
 	// Парсинг аргументов командной строки
 	var showStats bool
+	var seed int64
+	var scenarioPath, checkpointPath, resumePath, eventsPath, serveAddr string
+	var checkpointEvery, outputEvery uint64
+	var outputAppend bool
+	var outputs []src.OutputSpec
 	flag.BoolVar(&showStats, "stat", false, "Показать подробную статистику")
+	flag.Int64Var(&seed, "seed", 0, "Зерно генератора случайных чисел (0 - выбрать по времени)")
+	flag.StringVar(&scenarioPath, "scenario", "", "Файл сценария (JSON) с параметрами эксперимента")
+	flag.StringVar(&checkpointPath, "checkpoint", "checkpoint.json", "Файл для сохранения контрольных точек")
+	flag.Uint64Var(&checkpointEvery, "checkpoint-every", 0, "Сохранять контрольную точку каждые N часов (0 - не сохранять)")
+	flag.StringVar(&resumePath, "resume", "", "Продолжить симуляцию с контрольной точки")
+	flag.StringVar(&eventsPath, "events", "", "Файл журнала событий (JSONL)")
+	flag.Func("output", "Приемник состояния людей в виде формат:путь (csv, jsonl, parquet), можно указать несколько раз (по умолчанию csv:log.csv)", func(value string) error {
+		spec, err := src.ParseOutputSpec(value)
+		if err != nil {
+			return err
+		}
+		outputs = append(outputs, spec)
+		return nil
+	})
+	flag.BoolVar(&outputAppend, "output-append", false, "Дописывать в существующие файлы вывода вместо перезаписи")
+	flag.Uint64Var(&outputEvery, "output-every", 1, "Записывать состояние людей каждые N часов")
+	flag.StringVar(&serveAddr, "serve", "", "Адрес HTTP API для управления симуляцией, например :8080 (симуляция ждет команды start)")
 	flag.Parse()
 
+	if outputEvery == 0 {
+		log.Fatalf("Invalid -output-every: must be positive")
+	}
+
 	// Создать и запустить симуляцию
 	simulation := src.NewDefaultSimulation(showStats)
+	simulation.Seed = seed
+	simulation.ScenarioPath = scenarioPath
+	simulation.CheckpointPath = checkpointPath
+	simulation.CheckpointEvery = checkpointEvery
+	simulation.ResumePath = resumePath
+	simulation.EventsPath = eventsPath
+	if len(outputs) > 0 {
+		simulation.Outputs = outputs
+	}
+	simulation.OutputAppend = outputAppend
+	simulation.OutputEvery = outputEvery
+	simulation.ServeAddr = serveAddr
 	if err := simulation.Run(); err != nil {
 		log.Fatalf("Simulation failed: %v", err)
 	}
 }
+
+// configFileFlags регистрирует флаги путей к actions.ini, local.ini и global.ini.
+// Возвращенная функция после разбора флагов определяет пути: флаг, затем сценарий, затем значения по умолчанию
+func configFileFlags(flags *flag.FlagSet) func() (actionsPath, localPath, globalPath string, err error) {
+	scenarioPath := flags.String("scenario", "", "Файл сценария (JSON), из которого берутся пути к файлам конфигурации")
+	actions := flags.String("actions", "", "Файл действий (по умолчанию из сценария или "+config.ActionsFile+")")
+	local := flags.String("local", "", "Файл локальных целей (по умолчанию из сценария или "+config.LocalTargetsFile+")")
+	global := flags.String("global", "", "Файл глобальных целей (по умолчанию из сценария или "+config.GlobalTargetsFile+")")
+
+	return func() (string, string, string, error) {
+		if *scenarioPath != "" {
+			scenario, err := config.LoadScenario(*scenarioPath)
+			if err != nil {
+				return "", "", "", err
+			}
+			scenario.Apply()
+		}
+		if *actions == "" {
+			*actions = config.ActionsFile
+		}
+		if *local == "" {
+			*local = config.LocalTargetsFile
+		}
+		if *global == "" {
+			*global = config.GlobalTargetsFile
+		}
+		return *actions, *local, *global, nil
+	}
+}
+
+// lint проверяет actions.ini, local.ini и global.ini и возвращает код выхода (1 - есть ошибки)
+func lint(args []string) int {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	configFiles := configFileFlags(flags)
+	flags.Parse(args)
+
+	actionsPath, localPath, globalPath, err := configFiles()
+	if err != nil {
+		log.Fatalf("Lint failed: %v", err)
+	}
+
+	issues, err := src.LintConfig(actionsPath, localPath, globalPath)
+	if err != nil {
+		log.Fatalf("Lint failed: %v", err)
+	}
+
+	errors, warnings := 0, 0
+	for _, issue := range issues {
+		fmt.Println(issue)
+		if issue.Severity == src.LintError {
+			errors++
+		} else {
+			warnings++
+		}
+	}
+	fmt.Printf("%d errors, %d warnings\n", errors, warnings)
+
+	if errors > 0 {
+		return 1
+	}
+	return 0
+}
+
+// graph выводит граф целей и действий в формате DOT или JSON
+func graph(args []string) {
+	flags := flag.NewFlagSet("graph", flag.ExitOnError)
+	configFiles := configFileFlags(flags)
+	format := flags.String("format", "dot", "Формат графа: dot или json")
+	outputPath := flags.String("o", "", "Файл для графа (по умолчанию стандартный вывод)")
+	flags.Parse(args)
+
+	actionsPath, localPath, globalPath, err := configFiles()
+	if err != nil {
+		log.Fatalf("Graph failed: %v", err)
+	}
+
+	goals, err := src.LoadGoalGraph(actionsPath, localPath, globalPath)
+	if err != nil {
+		log.Fatalf("Graph failed: %v", err)
+	}
+
+	var write func(io.Writer) error
+	switch *format {
+	case "dot":
+		write = goals.WriteDOT
+	case "json":
+		write = goals.WriteJSON
+	default:
+		log.Fatalf("Invalid -format %q: must be dot or json", *format)
+	}
+
+	out := os.Stdout
+	if *outputPath != "" {
+		out, err = os.Create(*outputPath)
+		if err != nil {
+			log.Fatalf("Graph failed: %v", err)
+		}
+		defer out.Close()
+	}
+
+	if err := write(out); err != nil {
+		log.Fatalf("Graph failed: %v", err)
+	}
+}
@@ -0,0 +1,817 @@
+package src
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/fallra1n/humanity/src/components"
+	"github.com/fallra1n/humanity/src/utils"
+)
+
+// Версия формата контрольной точки
+const checkpointVersion = 13
+
+// checkpoint содержит полное состояние мира на границе часа.
+// Указатели заменены ссылками: люди - по ID из GlobalHumanStorage,
+// здания - по городу и ID здания, вакансии - по городу и ID вакансии,
+// действия и цели - по имени (сами определения загружаются из ini файлов)
+type checkpoint struct {
+	Version        int              `json:"version"`
+	Tick           uint64           `json:"tick"`
+	Seed           int64            `json:"seed"`
+	RandomState    uint64           `json:"random_state"`
+	HumanCount     int              `json:"human_count"`
+	HouseholdCount int              `json:"household_count"`
+	Cities         []cityState      `json:"cities"`
+	People         []humanState     `json:"people"`
+	Archive        []humanState     `json:"archive,omitempty"` // умершие
+	Households     []householdState `json:"households"`
+}
+
+type cityState struct {
+	Name      string          `json:"name"`
+	Buildings []buildingState `json:"buildings"`
+	Humans    []int           `json:"humans"`
+	Paths     []pathState     `json:"paths,omitempty"`
+}
+
+type householdState struct {
+	ID            int          `json:"id"`
+	Members       []int        `json:"members"`
+	Residence     *buildingRef `json:"residence,omitempty"`
+	Budget        int64        `json:"budget"`
+	Income        int64        `json:"income"`
+	Expenses      int64        `json:"expenses"`
+	MonthExpenses int64        `json:"month_expenses"`
+}
+
+type pathState struct {
+	To    string `json:"to"`
+	Price uint64 `json:"price"`
+	Time  uint64 `json:"time"`
+}
+
+type buildingState struct {
+	ID             int        `json:"id"`
+	Type           string     `json:"type"`
+	Name           string     `json:"name"`
+	Capacity       int        `json:"capacity"`
+	Occupied       int        `json:"occupied"`
+	ApartmentPrice int64      `json:"apartment_price"`
+	Lat            float64    `json:"lat"`
+	Lon            float64    `json:"lon"`
+	Residents      []int      `json:"residents,omitempty"`
+	Owners         []int      `json:"owners,omitempty"`
+	Jobs           []jobState `json:"jobs,omitempty"`
+}
+
+type jobState struct {
+	Vacancies []vacancyState `json:"vacancies"`
+}
+
+type vacancyState struct {
+	ID           int      `json:"id"`
+	Payment      int      `json:"payment"`
+	RequiredTags []string `json:"required_tags,omitempty"`
+	VacantPlaces uint64   `json:"vacant_places"`
+}
+
+type buildingRef struct {
+	City string `json:"city"`
+	ID   int    `json:"id"`
+}
+
+type vacancyRef struct {
+	City string `json:"city"`
+	ID   int    `json:"id"`
+}
+
+type splashState struct {
+	Name       string   `json:"name"`
+	Tags       []string `json:"tags"`
+	AppearTime uint64   `json:"appear_time"`
+	LifeLength uint64   `json:"life_length"`
+	Intensity  float64  `json:"intensity"`
+	HalfLife   uint64   `json:"half_life,omitempty"`
+}
+
+type globalTargetState struct {
+	Name         string   `json:"name"`
+	Possible     []string `json:"possible"`
+	Executed     []string `json:"executed"`
+	LastProgress uint64   `json:"last_progress"`
+}
+
+type humanState struct {
+	ID                     int                 `json:"id"`
+	Age                    float64             `json:"age"`
+	Gender                 string              `json:"gender"`
+	MaritalStatus          string              `json:"marital_status"`
+	Spouse                 int                 `json:"spouse"`
+	DivorceCount           int                 `json:"divorce_count,omitempty"`
+	SpouseBond             float64             `json:"spouse_bond,omitempty"`
+	IsPregnant             bool                `json:"is_pregnant"`
+	PregnancyTime          uint64              `json:"pregnancy_time"`
+	Dead                   bool                `json:"dead"`
+	DeathCause             string              `json:"death_cause,omitempty"`
+	BusyHours              uint64              `json:"busy_hours"`
+	Activity               string              `json:"activity,omitempty"`
+	ActivityGlobal         string              `json:"activity_global,omitempty"`
+	ActivityLocal          string              `json:"activity_local,omitempty"`
+	ActivityBuilding       *buildingRef        `json:"activity_building,omitempty"`
+	Money                  int64               `json:"money"`
+	Job                    *vacancyRef         `json:"job,omitempty"`
+	JobTime                uint64              `json:"job_time"`
+	UnemployedTime         uint64              `json:"unemployed_time"`
+	HomeLocation           string              `json:"home_location"`
+	CurrentBuilding        *buildingRef        `json:"current_building,omitempty"`
+	WorkBuilding           *buildingRef        `json:"work_building,omitempty"`
+	ResidentialBuilding    *buildingRef        `json:"residential_building,omitempty"`
+	School                 *buildingRef        `json:"school,omitempty"`
+	AtSchool               bool                `json:"at_school,omitempty"`
+	SchoolHours            uint64              `json:"school_hours,omitempty"`
+	MissedSchoolHours      uint64              `json:"missed_school_hours,omitempty"`
+	Health                 float64             `json:"health"`
+	Illness                string              `json:"illness,omitempty"`
+	IllnessHours           uint64              `json:"illness_hours,omitempty"`
+	Hospital               *buildingRef        `json:"hospital,omitempty"`
+	HospitalHours          uint64              `json:"hospital_hours,omitempty"`
+	SickLeaveHours         uint64              `json:"sick_leave_hours,omitempty"`
+	Parents                map[int]float64     `json:"parents,omitempty"`
+	Family                 map[int]float64     `json:"family,omitempty"`
+	Children               map[int]float64     `json:"children,omitempty"`
+	Friends                map[int]float64     `json:"friends,omitempty"`
+	Splashes               []splashState       `json:"splashes,omitempty"`
+	GlobalTargets          []globalTargetState `json:"global_targets,omitempty"`
+	CompletedGlobalTargets []globalTargetState `json:"completed_global_targets,omitempty"`
+	AbandonedGoals         map[string]uint64   `json:"abandoned_goals,omitempty"`
+	LocalProgress          map[string][]string `json:"local_progress,omitempty"` // локальная цель -> выполненные действия
+	Items                  map[string]int64    `json:"items,omitempty"`
+	Attributes             map[string]float64  `json:"attributes,omitempty"`
+	RandomState            uint64              `json:"random_state"`
+}
+
+// SaveCheckpoint сохраняет полное состояние симуляции в файл.
+// Файл сначала пишется во временный, а затем переименовывается,
+// чтобы падение процесса во время записи не испортило предыдущую точку
+func (s *Simulation) SaveCheckpoint(path string) error {
+	cp := checkpoint{
+		Version:        checkpointVersion,
+		Tick:           utils.GlobalTick.Get(),
+		Seed:           s.Seed,
+		RandomState:    utils.GlobalRandom.State(),
+		HumanCount:     components.GlobalHumanStorage.Count(),
+		HouseholdCount: components.HouseholdCount(),
+	}
+
+	for _, city := range s.Cities {
+		cp.Cities = append(cp.Cities, saveCity(city))
+	}
+	for _, person := range s.people {
+		cp.People = append(cp.People, saveHuman(person))
+	}
+	for _, person := range s.archive {
+		cp.Archive = append(cp.Archive, saveHuman(person))
+	}
+	households := components.Households(s.people)
+	sort.Slice(households, func(i, j int) bool { return households[i].ID < households[j].ID })
+	for _, household := range households {
+		cp.Households = append(cp.Households, saveHousehold(household))
+	}
+	data, err := json.Marshal(&cp)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %v", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint %s: %v", path, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to write checkpoint %s: %v", path, err)
+	}
+
+	return nil
+}
+
+// LoadCheckpoint восстанавливает состояние симуляции из файла.
+// Действия и цели должны быть уже загружены (loadInitData)
+func (s *Simulation) LoadCheckpoint(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read checkpoint %s: %v", path, err)
+	}
+
+	var cp checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return fmt.Errorf("failed to decode checkpoint %s: %v", path, err)
+	}
+	if cp.Version != checkpointVersion {
+		return fmt.Errorf("unsupported checkpoint version %d in %s", cp.Version, path)
+	}
+	if len(cp.Cities) == 0 {
+		return fmt.Errorf("checkpoint %s contains no cities", path)
+	}
+
+	r := &restorer{
+		cities:    make(map[string]*components.Location),
+		buildings: make(map[buildingRef]*components.Building),
+		vacancies: make(map[vacancyRef]*components.Vacancy),
+		humans:    make(map[int]*components.Human),
+	}
+	r.actions, r.localTargets, r.globalTargets, err = CreateNameMaps(s.actions, s.localTargets, s.globalTargets)
+	if err != nil {
+		return err
+	}
+
+	// Города и здания
+	var cities []*components.Location
+	for _, cs := range cp.Cities {
+		cities = append(cities, r.restoreCity(cs))
+	}
+
+	// Дороги между городами
+	for _, cs := range cp.Cities {
+		from := r.cities[cs.Name]
+		for _, ps := range cs.Paths {
+			to, exists := r.cities[ps.To]
+			if !exists {
+				return fmt.Errorf("path from %s to unknown city %q in checkpoint", cs.Name, ps.To)
+			}
+			from.Paths[&components.Path{From: from, To: to, Price: ps.Price, Time: ps.Time}] = true
+		}
+	}
+
+	// Люди: сначала создать всех, затем связать указатели
+	for _, hs := range append(cp.People, cp.Archive...) {
+		r.humans[hs.ID] = &components.Human{}
+	}
+	people, err := r.restoreHumans(cp.People)
+	if err != nil {
+		return err
+	}
+	archive, err := r.restoreHumans(cp.Archive)
+	if err != nil {
+		return err
+	}
+
+	// Связать жителей зданий и городов
+	for _, cs := range cp.Cities {
+		city := r.cities[cs.Name]
+		for _, id := range cs.Humans {
+			if human, exists := r.humans[id]; exists {
+				city.Humans[human] = true
+			}
+		}
+		for _, bs := range cs.Buildings {
+			building := r.buildings[buildingRef{City: cs.Name, ID: bs.ID}]
+			for _, id := range bs.Residents {
+				if human, exists := r.humans[id]; exists {
+					building.Residents[human] = true
+				}
+			}
+			for _, id := range bs.Owners {
+				if human, exists := r.humans[id]; exists {
+					building.Owners[human] = true
+				}
+			}
+		}
+	}
+
+	// Домохозяйства
+	for _, hs := range cp.Households {
+		if err := r.restoreHousehold(hs); err != nil {
+			return err
+		}
+	}
+	components.RestoreHouseholdCount(cp.HouseholdCount)
+
+	// Хранилище ID людей, время и генератор случайных чисел
+	ids := make(map[interface{}]int, len(r.humans))
+	for id, human := range r.humans {
+		ids[human] = id
+	}
+	components.GlobalHumanStorage.Restore(ids, cp.HumanCount)
+	utils.GlobalTick.Set(cp.Tick)
+	s.Seed = cp.Seed
+	utils.SetGlobalSeed(cp.Seed)
+	utils.GlobalRandom.SetState(cp.RandomState)
+
+	s.Cities = cities
+	s.people = people
+	s.archive = archive
+
+	return nil
+}
+
+// maybeCheckpoint сохраняет контрольную точку, если подошло время
+func (s *Simulation) maybeCheckpoint() error {
+	if s.CheckpointEvery == 0 || utils.GlobalTick.Get()%s.CheckpointEvery != 0 {
+		return nil
+	}
+	return s.SaveCheckpoint(s.CheckpointPath)
+}
+
+// restoreHumans восстанавливает список людей; все люди должны быть уже созданы в r.humans
+func (r *restorer) restoreHumans(states []humanState) ([]*components.Human, error) {
+	var people []*components.Human
+	for _, hs := range states {
+		person, err := r.restoreHuman(hs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore human %d: %v", hs.ID, err)
+		}
+		people = append(people, person)
+	}
+	return people, nil
+}
+
+// saveCity сериализует город вместе со зданиями и вакансиями
+func saveCity(city *components.Location) cityState {
+	cs := cityState{
+		Name:   city.Name,
+		Humans: humanIDs(city.Humans),
+	}
+
+	for path := range city.Paths {
+		cs.Paths = append(cs.Paths, pathState{To: path.To.Name, Price: path.Price, Time: path.Time})
+	}
+	sort.Slice(cs.Paths, func(i, j int) bool { return cs.Paths[i].To < cs.Paths[j].To })
+
+	var buildings []*components.Building
+	for building := range city.Buildings {
+		buildings = append(buildings, building)
+	}
+	sort.Slice(buildings, func(i, j int) bool { return buildings[i].ID < buildings[j].ID })
+
+	for _, building := range buildings {
+		bs := buildingState{
+			ID:             building.ID,
+			Type:           string(building.Type),
+			Name:           building.Name,
+			Capacity:       building.Capacity,
+			Occupied:       building.Occupied,
+			ApartmentPrice: building.ApartmentPrice,
+			Lat:            building.Lat,
+			Lon:            building.Lon,
+			Residents:      humanIDs(building.Residents),
+			Owners:         humanIDs(building.Owners),
+		}
+
+		var jobs []*components.Job
+		for job := range building.Jobs {
+			jobs = append(jobs, job)
+		}
+		sort.Slice(jobs, func(i, j int) bool { return minVacancyID(jobs[i]) < minVacancyID(jobs[j]) })
+
+		for _, job := range jobs {
+			var js jobState
+			for vacancy, count := range job.VacantPlaces {
+				js.Vacancies = append(js.Vacancies, vacancyState{
+					ID:           vacancy.ID,
+					Payment:      vacancy.Payment,
+					RequiredTags: sortedKeys(vacancy.RequiredTags),
+					VacantPlaces: count,
+				})
+			}
+			sort.Slice(js.Vacancies, func(i, j int) bool { return js.Vacancies[i].ID < js.Vacancies[j].ID })
+			bs.Jobs = append(bs.Jobs, js)
+		}
+
+		cs.Buildings = append(cs.Buildings, bs)
+	}
+
+	return cs
+}
+
+// saveHuman сериализует человека, заменяя указатели на ID
+func saveHuman(h *components.Human) humanState {
+	hs := humanState{
+		ID:                  components.GlobalHumanStorage.Get(h),
+		Age:                 h.Age,
+		Gender:              string(h.Gender),
+		MaritalStatus:       string(h.MaritalStatus),
+		Spouse:              components.GlobalHumanStorage.Get(h.Spouse),
+		DivorceCount:        h.DivorceCount,
+		SpouseBond:          h.SpouseBond,
+		IsPregnant:          h.IsPregnant,
+		PregnancyTime:       h.PregnancyTime,
+		Dead:                h.Dead,
+		DeathCause:          string(h.DeathCause),
+		BusyHours:           h.BusyHours,
+		ActivityBuilding:    refBuilding(h.ActivityBuilding),
+		Money:               h.Money,
+		JobTime:             h.JobTime,
+		UnemployedTime:      h.UnemployedTime,
+		CurrentBuilding:     refBuilding(h.CurrentBuilding),
+		WorkBuilding:        refBuilding(h.WorkBuilding),
+		ResidentialBuilding: refBuilding(h.ResidentialBuilding),
+		School:              refBuilding(h.School),
+		AtSchool:            h.AtSchool,
+		SchoolHours:         h.SchoolHours,
+		MissedSchoolHours:   h.MissedSchoolHours,
+		Health:              h.Health,
+		Illness:             h.Illness,
+		IllnessHours:        h.IllnessHours,
+		Hospital:            refBuilding(h.Hospital),
+		HospitalHours:       h.HospitalHours,
+		SickLeaveHours:      h.SickLeaveHours,
+		Parents:             relationIDs(h.Parents),
+		Family:              relationIDs(h.Family),
+		Children:            relationIDs(h.Children),
+		Friends:             relationIDs(h.Friends),
+		Items:               h.Items,
+		Attributes:          h.Attributes,
+		RandomState:         h.Rand.State(),
+	}
+	if h.Spouse == nil {
+		hs.Spouse = -1
+	}
+	if h.Activity != nil {
+		hs.Activity = h.Activity.Name
+	}
+	if h.ActivityGlobal != nil && h.GlobalTargets[h.ActivityGlobal] {
+		hs.ActivityGlobal = h.ActivityGlobal.Name
+	}
+	if h.ActivityLocal != nil {
+		hs.ActivityLocal = h.ActivityLocal.Name
+	}
+	if h.HomeLocation != nil {
+		hs.HomeLocation = h.HomeLocation.Name
+	}
+	if h.Job != nil {
+		hs.Job = &vacancyRef{City: h.Job.Parent.HomeLocation.Name, ID: h.Job.ID}
+	}
+	for _, splash := range h.Splashes {
+		hs.Splashes = append(hs.Splashes, splashState{
+			Name:       splash.Name,
+			Tags:       sortedKeys(splash.Tags),
+			AppearTime: splash.AppearTime,
+			LifeLength: splash.LifeLength,
+			Intensity:  splash.Intensity,
+			HalfLife:   splash.HalfLife,
+		})
+	}
+	hs.GlobalTargets = saveGlobalTargets(h.GlobalTargets)
+	hs.CompletedGlobalTargets = saveGlobalTargets(h.CompletedGlobalTargets)
+	if len(h.AbandonedGoals) > 0 {
+		hs.AbandonedGoals = h.AbandonedGoals
+	}
+	for target, progress := range h.LocalProgress {
+		if hs.LocalProgress == nil {
+			hs.LocalProgress = make(map[string][]string)
+		}
+		hs.LocalProgress[target.Name] = actionNames(progress.ActionsExecuted)
+	}
+
+	return hs
+}
+
+// saveHousehold сериализует домохозяйство, заменяя членов на их ID
+func saveHousehold(household *components.Household) householdState {
+	hs := householdState{
+		ID:            household.ID,
+		Residence:     refBuilding(household.Residence),
+		Budget:        household.Budget,
+		Income:        household.Income,
+		Expenses:      household.Expenses,
+		MonthExpenses: household.MonthExpenses,
+	}
+	for _, member := range household.SortedMembers() {
+		hs.Members = append(hs.Members, components.GlobalHumanStorage.Get(member))
+	}
+	return hs
+}
+
+// saveGlobalTargets сериализует персональные копии глобальных целей
+func saveGlobalTargets(targets map[*components.GlobalTarget]bool) []globalTargetState {
+	var states []globalTargetState
+	for target := range targets {
+		states = append(states, globalTargetState{
+			Name:         target.Name,
+			Possible:     localTargetNames(target.TargetsPossible),
+			Executed:     localTargetNames(target.TargetsExecuted),
+			LastProgress: target.LastProgress,
+		})
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Name < states[j].Name })
+	return states
+}
+
+// restorer хранит промежуточные таблицы для восстановления указателей
+type restorer struct {
+	cities        map[string]*components.Location
+	buildings     map[buildingRef]*components.Building
+	vacancies     map[vacancyRef]*components.Vacancy
+	humans        map[int]*components.Human
+	actions       map[string]*components.Action
+	localTargets  map[string]*components.LocalTarget
+	globalTargets map[string]*components.GlobalTarget
+}
+
+// restoreCity воссоздает город, его здания, работы и вакансии
+func (r *restorer) restoreCity(cs cityState) *components.Location {
+	city := &components.Location{
+		Name:      cs.Name,
+		Buildings: make(map[*components.Building]bool),
+		Jobs:      make(map[*components.Job]bool),
+		Humans:    make(map[*components.Human]bool),
+		Paths:     make(map[*components.Path]bool),
+	}
+	r.cities[cs.Name] = city
+
+	for _, bs := range cs.Buildings {
+		building := &components.Building{
+			ID:             bs.ID,
+			Type:           components.BuildingType(bs.Type),
+			Name:           bs.Name,
+			Location:       city,
+			Jobs:           make(map[*components.Job]bool),
+			Residents:      make(map[*components.Human]bool),
+			Owners:         make(map[*components.Human]bool),
+			Capacity:       bs.Capacity,
+			Occupied:       bs.Occupied,
+			ApartmentPrice: bs.ApartmentPrice,
+			Lat:            bs.Lat,
+			Lon:            bs.Lon,
+		}
+
+		for _, js := range bs.Jobs {
+			job := &components.Job{
+				VacantPlaces: make(map[*components.Vacancy]uint64),
+				HomeLocation: city,
+				Building:     building,
+			}
+			for _, vs := range js.Vacancies {
+				vacancy := &components.Vacancy{
+					ID:           vs.ID,
+					Parent:       job,
+					RequiredTags: make(map[string]bool),
+					Payment:      vs.Payment,
+				}
+				for _, tag := range vs.RequiredTags {
+					vacancy.RequiredTags[tag] = true
+				}
+				job.VacantPlaces[vacancy] = vs.VacantPlaces
+				r.vacancies[vacancyRef{City: cs.Name, ID: vs.ID}] = vacancy
+			}
+			building.Jobs[job] = true
+			city.Jobs[job] = true
+		}
+
+		city.Buildings[building] = true
+		r.buildings[buildingRef{City: cs.Name, ID: bs.ID}] = building
+	}
+
+	return city
+}
+
+// restoreHuman заполняет заранее созданного человека сохраненным состоянием
+func (r *restorer) restoreHuman(hs humanState) (*components.Human, error) {
+	h := r.humans[hs.ID]
+
+	h.Age = hs.Age
+	h.Gender = components.Gender(hs.Gender)
+	h.MaritalStatus = components.MaritalStatus(hs.MaritalStatus)
+	h.Spouse = r.humans[hs.Spouse]
+	h.DivorceCount = hs.DivorceCount
+	h.SpouseBond = hs.SpouseBond
+	h.IsPregnant = hs.IsPregnant
+	h.PregnancyTime = hs.PregnancyTime
+	h.Dead = hs.Dead
+	h.DeathCause = components.DeathCause(hs.DeathCause)
+	h.BusyHours = hs.BusyHours
+	h.ActivityBuilding = r.building(hs.ActivityBuilding)
+	h.Money = hs.Money
+	h.JobTime = hs.JobTime
+	h.UnemployedTime = hs.UnemployedTime
+	h.HomeLocation = r.cities[hs.HomeLocation]
+	h.CurrentBuilding = r.building(hs.CurrentBuilding)
+	h.WorkBuilding = r.building(hs.WorkBuilding)
+	h.ResidentialBuilding = r.building(hs.ResidentialBuilding)
+	h.School = r.building(hs.School)
+	h.AtSchool = hs.AtSchool
+	h.SchoolHours = hs.SchoolHours
+	h.MissedSchoolHours = hs.MissedSchoolHours
+	h.Health = hs.Health
+	h.Illness = hs.Illness
+	h.IllnessHours = hs.IllnessHours
+	h.Hospital = r.building(hs.Hospital)
+	h.HospitalHours = hs.HospitalHours
+	h.SickLeaveHours = hs.SickLeaveHours
+	h.Parents = r.relations(hs.Parents)
+	h.Family = r.relations(hs.Family)
+	h.Children = r.relations(hs.Children)
+	h.Friends = r.relations(hs.Friends)
+	h.Splashes = make([]*components.Splash, 0, len(hs.Splashes))
+	h.Items = make(map[string]int64)
+	h.Attributes = make(map[string]float64)
+	h.AbandonedGoals = make(map[string]uint64)
+	h.Rand = utils.NewRandom(0)
+	h.Rand.SetState(hs.RandomState)
+
+	if hs.Job != nil {
+		vacancy, exists := r.vacancies[*hs.Job]
+		if !exists {
+			return nil, fmt.Errorf("unknown vacancy %d in %s", hs.Job.ID, hs.Job.City)
+		}
+		h.Job = vacancy
+	}
+
+	if hs.Activity != "" {
+		action, exists := r.actions[hs.Activity]
+		if !exists {
+			return nil, fmt.Errorf("unknown action %q in checkpoint", hs.Activity)
+		}
+		h.Activity = action
+	}
+
+	for _, ss := range hs.Splashes {
+		splash := components.NewSplash(ss.Name, ss.Tags, ss.LifeLength)
+		splash.AppearTime = ss.AppearTime
+		splash.Intensity = ss.Intensity
+		splash.HalfLife = ss.HalfLife
+		h.Splashes = append(h.Splashes, splash)
+	}
+
+	for item, count := range hs.Items {
+		h.Items[item] = count
+	}
+	for name, value := range hs.Attributes {
+		h.Attributes[name] = value
+	}
+	for name, tick := range hs.AbandonedGoals {
+		h.AbandonedGoals[name] = tick
+	}
+
+	var err error
+	if h.GlobalTargets, err = r.globalTargetCopies(hs.GlobalTargets); err != nil {
+		return nil, err
+	}
+	if h.CompletedGlobalTargets, err = r.globalTargetCopies(hs.CompletedGlobalTargets); err != nil {
+		return nil, err
+	}
+
+	// Глобальная цель действия - одна из персональных копий
+	for target := range h.GlobalTargets {
+		if target.Name == hs.ActivityGlobal {
+			h.ActivityGlobal = target
+		}
+	}
+	if hs.ActivityLocal != "" {
+		target, exists := r.localTargets[hs.ActivityLocal]
+		if !exists {
+			return nil, fmt.Errorf("unknown local target %q in checkpoint", hs.ActivityLocal)
+		}
+		h.ActivityLocal = target
+	}
+
+	h.LocalProgress = make(map[*components.LocalTarget]*components.LocalTargetProgress)
+	for name, actions := range hs.LocalProgress {
+		target, exists := r.localTargets[name]
+		if !exists {
+			return nil, fmt.Errorf("unknown local target %q in checkpoint", name)
+		}
+		progress := &components.LocalTargetProgress{ActionsExecuted: make(map[*components.Action]bool)}
+		for _, actionName := range actions {
+			action, exists := r.actions[actionName]
+			if !exists {
+				return nil, fmt.Errorf("unknown action %q in checkpoint", actionName)
+			}
+			progress.ActionsExecuted[action] = true
+		}
+		h.LocalProgress[target] = progress
+	}
+
+	return h, nil
+}
+
+// restoreHousehold воссоздает домохозяйство и связывает его с членами
+func (r *restorer) restoreHousehold(hs householdState) error {
+	household := &components.Household{
+		ID:            hs.ID,
+		Members:       make(map[*components.Human]bool),
+		Residence:     r.building(hs.Residence),
+		Budget:        hs.Budget,
+		Income:        hs.Income,
+		Expenses:      hs.Expenses,
+		MonthExpenses: hs.MonthExpenses,
+	}
+	for _, id := range hs.Members {
+		member, exists := r.humans[id]
+		if !exists {
+			return fmt.Errorf("unknown member %d of household %d in checkpoint", id, hs.ID)
+		}
+		household.Members[member] = true
+		member.Household = household
+	}
+	return nil
+}
+
+// globalTargetCopies воссоздает персональные копии глобальных целей
+func (r *restorer) globalTargetCopies(states []globalTargetState) (map[*components.GlobalTarget]bool, error) {
+	targets := make(map[*components.GlobalTarget]bool)
+	for _, gs := range states {
+		definition, exists := r.globalTargets[gs.Name]
+		if !exists {
+			return nil, fmt.Errorf("unknown global target %q", gs.Name)
+		}
+
+		target := definition.Copy()
+		target.TargetsPossible = make(map[*components.LocalTarget]bool)
+		target.LastProgress = gs.LastProgress
+		for _, name := range gs.Possible {
+			if local, exists := r.localTargets[name]; exists {
+				target.TargetsPossible[local] = true
+			}
+		}
+		for _, name := range gs.Executed {
+			if local, exists := r.localTargets[name]; exists {
+				target.TargetsExecuted[local] = true
+			}
+		}
+		targets[target] = true
+	}
+	return targets, nil
+}
+
+func (r *restorer) building(ref *buildingRef) *components.Building {
+	if ref == nil {
+		return nil
+	}
+	return r.buildings[*ref]
+}
+
+func (r *restorer) relations(ids map[int]float64) map[*components.Human]float64 {
+	relations := make(map[*components.Human]float64, len(ids))
+	for id, value := range ids {
+		if human, exists := r.humans[id]; exists {
+			relations[human] = value
+		}
+	}
+	return relations
+}
+
+// Вспомогательные функции сериализации
+
+func refBuilding(building *components.Building) *buildingRef {
+	if building == nil {
+		return nil
+	}
+	return &buildingRef{City: building.Location.Name, ID: building.ID}
+}
+
+func humanIDs(humans map[*components.Human]bool) []int {
+	ids := make([]int, 0, len(humans))
+	for human := range humans {
+		ids = append(ids, components.GlobalHumanStorage.Get(human))
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func relationIDs(relations map[*components.Human]float64) map[int]float64 {
+	ids := make(map[int]float64, len(relations))
+	for human, value := range relations {
+		ids[components.GlobalHumanStorage.Get(human)] = value
+	}
+	return ids
+}
+
+func actionNames(actions map[*components.Action]bool) []string {
+	names := make([]string, 0, len(actions))
+	for action := range actions {
+		names = append(names, action.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func localTargetNames(targets map[*components.LocalTarget]bool) []string {
+	names := make([]string, 0, len(targets))
+	for target := range targets {
+		names = append(names, target.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func minVacancyID(job *components.Job) int {
+	min := -1
+	for vacancy := range job.VacantPlaces {
+		if min == -1 || vacancy.ID < min {
+			min = vacancy.ID
+		}
+	}
+	return min
+}
@@ -0,0 +1,80 @@
+package src
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fallra1n/humanity/src/components"
+	"github.com/fallra1n/humanity/src/config"
+	"github.com/fallra1n/humanity/src/utils"
+)
+
+// Контрольная точка, загруженная и сохраненная заново, должна совпадать с исходной
+func TestCheckpointRoundTrip(t *testing.T) {
+	useRepoConfigFiles(t)
+	components.SetPlanner(config.PlannerType)
+	utils.GlobalTick.Set(0)
+
+	original := NewSimulation(0, 500, false)
+	original.Seed = 42
+	if err := original.loadInitData(); err != nil {
+		t.Fatal(err)
+	}
+	original.initializeRandom()
+	original.initializeCities()
+	original.initializePopulation()
+	if err := original.runSimulationLoop(); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.json")
+	if err := original.SaveCheckpoint(first); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := NewSimulation(0, 500, false)
+	if err := restored.loadInitData(); err != nil {
+		t.Fatal(err)
+	}
+	if err := restored.LoadCheckpoint(first); err != nil {
+		t.Fatal(err)
+	}
+	if got := utils.GlobalTick.Get(); got != 500 {
+		t.Fatalf("restored tick = %d, want 500", got)
+	}
+	if len(restored.people) != len(original.people) || len(restored.archive) != len(original.archive) {
+		t.Fatalf("restored %d people and %d dead, want %d and %d",
+			len(restored.people), len(restored.archive), len(original.people), len(original.archive))
+	}
+
+	second := filepath.Join(dir, "second.json")
+	if err := restored.SaveCheckpoint(second); err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := os.ReadFile(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("checkpoint changed after save and load (%d bytes, want %d)", len(got), len(want))
+	}
+}
+
+// useRepoConfigFiles указывает на файлы конфигурации репозитория (тесты запускаются из backend/src)
+func useRepoConfigFiles(t *testing.T) {
+	t.Helper()
+	files := []*string{&config.ActionsFile, &config.LocalTargetsFile, &config.GlobalTargetsFile, &config.SplashesFile}
+	for _, file := range files {
+		previous := *file
+		*file = filepath.Join("..", previous)
+		t.Cleanup(func() { *file = previous })
+	}
+}
@@ -2,10 +2,7 @@ package components
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
-
-	"github.com/fallra1n/humanity/src/utils"
 )
 
 // Action представляет конкретное действие, которое может быть выполнено
@@ -15,13 +12,15 @@ type Action struct {
 	TimeToExecute  int64
 	BonusMoney     int64
 	Tags           map[string]bool
-	Rules          map[string]int64
+	Rules          []*Rule
 	Items          map[string]int64
 	RemovableItems map[string]int64
+	Effects        []*Effect
+	BuildingType   BuildingType // Где выполняется действие (пусто - где угодно)
 }
 
 // NewAction создает новое действие из конфигурационных данных
-func NewAction(name string, price, timeToExecute int64, tags []string, rules, items, removableItems map[string]int64, bonusMoney int64) *Action {
+func NewAction(name string, price, timeToExecute int64, tags []string, rules []*Rule, items, removableItems map[string]int64, bonusMoney int64, effects []*Effect, buildingType BuildingType) *Action {
 	tagSet := make(map[string]bool)
 	for _, tag := range tags {
 		tagSet[tag] = true
@@ -36,66 +35,16 @@ func NewAction(name string, price, timeToExecute int64, tags []string, rules, it
 		Rules:          rules,
 		Items:          items,
 		RemovableItems: removableItems,
+		Effects:        effects,
+		BuildingType:   buildingType,
 	}
 }
 
 // Executable проверяет, может ли действие быть выполнено человеком
 func (a *Action) Executable(person *Human) bool {
-	comparisons1 := []string{">", "<", "="}
-	comparisons2 := []string{"<>", ">=", "<="}
-
-	for rule, value := range a.Rules {
-		comparison := ""
-
-		// Найти оператор сравнения
-		for _, op := range comparisons2 {
-			if strings.Contains(rule, op) {
-				comparison = op
-				break
-			}
-		}
-		if comparison == "" {
-			for _, op := range comparisons1 {
-				if strings.Contains(rule, op) {
-					comparison = op
-					break
-				}
-			}
-		}
-
-		if comparison != "" {
-			parts := strings.Split(rule, comparison)
-			if len(parts) != 2 {
-				continue
-			}
-
-			var values [2]int64
-			for i, part := range parts {
-				if utils.IsNatural(part) {
-					val, _ := strconv.ParseInt(part, 10, 64)
-					values[i] = val
-				} else {
-					// Обработка метрик
-					switch part {
-					case "cash":
-						values[i] = person.Money
-						for family := range person.Family {
-							values[i] += family.Money
-						}
-					case "job_time":
-						values[i] = int64(person.JobTime)
-					}
-				}
-			}
-
-			if !utils.Compare(values[0], comparison, values[1]) {
-				return false
-			}
-		} else {
-			// Проверка наличия предмета
-			if person.Items[rule] < value {
-				return false
-			}
+	for _, rule := range a.Rules {
+		if !rule.Eval(person) {
+			return false
 		}
 	}
 
@@ -109,10 +58,9 @@ func (a *Action) Executable(person *Human) bool {
 	return true
 }
 
-// Apply выполняет действие над человеком
+// Apply применяет результат выполненного действия к человеку.
+// Цену списывает performActions при начале действия
 func (a *Action) Apply(person *Human) {
-	person.Money -= a.Price
-
 	// Добавить предметы
 	for item, count := range a.Items {
 		person.Items[item] += count
@@ -128,9 +76,11 @@ func (a *Action) Apply(person *Human) {
 
 	person.Money += a.BonusMoney
 
-	// Особый случай: поиск работы
-	if a.Name == "find_job" {
-		findJob(person)
+	person.deferEvent(EventAction, []*Human{person}, map[string]interface{}{"action": a.Name, "price": a.Price})
+
+	// Эффекты применяются в порядке записи в actions.ini
+	for _, effect := range a.Effects {
+		effect.Apply(person)
 	}
 }
 
@@ -143,8 +93,8 @@ func (a *Action) String() string {
 
 	if len(a.Rules) > 0 {
 		sb.WriteString("  Rules:\n")
-		for rule, value := range a.Rules {
-			sb.WriteString(fmt.Sprintf("    %s [%d]\n", rule, value))
+		for _, rule := range a.Rules {
+			sb.WriteString(fmt.Sprintf("    %s\n", rule))
 		}
 	}
 
@@ -169,5 +119,12 @@ func (a *Action) String() string {
 		}
 	}
 
+	if len(a.Effects) > 0 {
+		sb.WriteString("  Effects:\n")
+		for _, effect := range a.Effects {
+			sb.WriteString(fmt.Sprintf("    %s\n", effect))
+		}
+	}
+
 	return sb.String()
 }
@@ -0,0 +1,205 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/fallra1n/humanity/src/config"
+	"github.com/fallra1n/humanity/src/utils"
+)
+
+// Выполнение действий во времени.
+// Действие с TimeToExecute > 0 занимает человека на столько свободных часов (BusyHours).
+// Если у действия задан тип здания, человек проводит эти часы в здании такого типа
+// в своем городе и занимает в нем место (Occupied, не больше Capacity).
+// Рабочие часы и сон прерывают действие: человек уходит на работу или домой,
+// освобождает место, и счетчик часов не уменьшается.
+// Результат действия (предметы, бонус, эффекты) и продвижение по целям засчитываются,
+// когда истекут все часы; оборванное действие (смерть, переезд) результата не дает.
+// Эффект !goto тоже занимает место в здании (ActivityBuilding), но только до следующего перемещения
+
+// startActivity начинает выполнение действия ради целей global и local и засчитывает текущий час
+func (h *Human) startActivity(action *Action, global *GlobalTarget, local *LocalTarget) {
+	if action.TimeToExecute <= 0 {
+		return
+	}
+
+	h.Activity = action
+	h.ActivityGlobal = global
+	h.ActivityLocal = local
+	h.BusyHours = uint64(action.TimeToExecute)
+	h.continueActivity()
+}
+
+// continueActivity продолжает действие в этом часу или прерывает его ради работы.
+// Места в зданиях общие, поэтому занять или освободить место можно только в последовательной фазе
+func (h *Human) continueActivity() {
+	if h.isWorkingHour() {
+		h.interruptActivity()
+		return
+	}
+	h.deferShared(h.attendActivity)
+}
+
+// interruptActivity освобождает место в здании на время работы или сна
+func (h *Human) interruptActivity() {
+	if h.ActivityBuilding != nil {
+		h.deferShared(h.leaveActivityBuilding)
+	}
+}
+
+// attendActivity проводит час за действием: занимает место в здании (если нужно)
+// и уменьшает счетчик оставшихся часов. Если свободных мест нет, человек ждет дома
+func (h *Human) attendActivity() {
+	if h.Activity == nil || h.Dead {
+		return
+	}
+
+	if h.Activity.BuildingType != "" && h.ActivityBuilding == nil {
+		buildings := h.activityBuildings()
+		if len(buildings) > 0 {
+			building := h.enterActivityBuilding(buildings)
+			if building == nil {
+				return
+			}
+			h.ActivityBuilding = building
+		}
+	}
+
+	if h.ActivityBuilding != nil {
+		h.CurrentBuilding = h.ActivityBuilding
+	}
+
+	h.BusyHours--
+	if h.BusyHours == 0 {
+		h.completeActivity()
+	}
+}
+
+// completeActivity применяет результат действия, у которого истекли все часы,
+// засчитывает продвижение по целям и завершает действие
+func (h *Human) completeActivity() {
+	action, global, local := h.Activity, h.ActivityGlobal, h.ActivityLocal
+
+	// Глобальная цель могла быть снята при пересмотре целей, пока шло действие
+	if !h.GlobalTargets[global] {
+		global = nil
+	}
+
+	action.Apply(h)
+	h.progressTargets(global, local, action)
+	h.stopActivity()
+
+	// Эффекты и события откладываются так же, как в параллельной фазе,
+	// но здесь уже последовательная фаза: выполнить их сразу
+	h.ApplySharedEffects()
+}
+
+// visitBuilding занимает место в случайном здании нужного типа в городе человека (эффект !goto).
+// Действие со своим зданием и больница важнее посещения; если мест нет, человек остается на месте
+func (h *Human) visitBuilding(buildingType BuildingType) {
+	if h.Dead || h.Hospital != nil || h.ActivityBuilding != nil {
+		return
+	}
+	if h.Activity != nil && h.Activity.BuildingType != "" {
+		return
+	}
+
+	var buildings []*Building
+	for _, building := range GetBuildings(h.HomeLocation) {
+		if building.Type == buildingType {
+			buildings = append(buildings, building)
+		}
+	}
+	building := h.enterActivityBuilding(buildings)
+	if building == nil {
+		return
+	}
+	h.ActivityBuilding = building
+	h.CurrentBuilding = building
+}
+
+// isVisiting сообщает, что человек занимает место в здании по эффекту !goto, а не ради действия
+func (h *Human) isVisiting() bool {
+	return h.ActivityBuilding != nil && (h.Activity == nil || h.Activity.BuildingType == "")
+}
+
+// activityBuildings возвращает здания нужного для действия типа в городе человека
+func (h *Human) activityBuildings() []*Building {
+	var buildings []*Building
+	for _, building := range GetBuildings(h.HomeLocation) {
+		if building.Type == h.Activity.BuildingType {
+			buildings = append(buildings, building)
+		}
+	}
+	return buildings
+}
+
+// enterActivityBuilding занимает место в случайном здании со свободными местами
+func (h *Human) enterActivityBuilding(buildings []*Building) *Building {
+	var available []*Building
+	for _, building := range buildings {
+		building.Mu.RLock()
+		if building.Occupied < building.Capacity {
+			available = append(available, building)
+		}
+		building.Mu.RUnlock()
+	}
+	if len(available) == 0 {
+		return nil
+	}
+
+	building := available[h.Rand.NextInt(len(available))]
+	if !building.AddVisitor() {
+		return nil
+	}
+	return building
+}
+
+// leaveActivityBuilding освобождает занятое для действия место
+func (h *Human) leaveActivityBuilding() {
+	building := h.ActivityBuilding
+	if building == nil {
+		return
+	}
+
+	building.RemoveVisitor()
+	h.ActivityBuilding = nil
+
+	if h.CurrentBuilding == building {
+		h.CurrentBuilding = h.ResidentialBuilding
+	}
+}
+
+// stopActivity завершает (или обрывает) выполнение действия и посещение здания.
+// Вызывается только в последовательной фазе
+func (h *Human) stopActivity() {
+	h.leaveActivityBuilding()
+	if h.Activity == nil {
+		return
+	}
+
+	h.Activity = nil
+	h.ActivityGlobal = nil
+	h.ActivityLocal = nil
+	h.BusyHours = 0
+}
+
+// isWorkingHour сообщает, должен ли человек сейчас быть на работе
+func (h *Human) isWorkingHour() bool {
+	tick := utils.GlobalTick.Get()
+	return utils.IsWorkTime(tick) && utils.IsWorkDay(tick) && h.Job != nil && h.WorkBuilding != nil
+}
+
+// ParseActivityBuildingType проверяет тип здания для действия (слово %тип в actions.ini).
+// Жилые дома не подходят: места в них - это квартиры
+func ParseActivityBuildingType(name string) (BuildingType, error) {
+	if BuildingType(name) == ResidentialHouse {
+		return "", fmt.Errorf("actions cannot take place in %s", name)
+	}
+	for _, buildingType := range config.BuildingTypes {
+		if name == buildingType {
+			return BuildingType(name), nil
+		}
+	}
+	return "", fmt.Errorf("unknown building type %q", name)
+}
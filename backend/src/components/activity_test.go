@@ -0,0 +1,55 @@
+package components
+
+import "testing"
+
+// Результат действия и продвижение по целям засчитываются, только когда истекли все часы действия
+func TestActivityAppliesOnCompletion(t *testing.T) {
+	study := NewAction("study", 100, 2, []string{"education"}, nil, map[string]int64{"diploma": 1}, nil, 50, nil, "")
+	local := NewLocalTarget("learn", []string{"education"}, []*Action{study})
+	global := NewGlobalTarget("career", []string{"education"}, 1, 1, nil, []*LocalTarget{local})
+
+	person := NewHuman(map[*Human]bool{}, nil, nil)
+	person.Money = 1000
+	person.GlobalTargets[global] = true
+
+	person.startActivity(study, global, local)
+	person.ApplySharedEffects()
+	if person.Activity != study || person.BusyHours != 1 {
+		t.Fatalf("activity = %v, busy hours = %d after the first hour", actionName(person.Activity), person.BusyHours)
+	}
+	if person.Items["diploma"] != 0 || person.Money != 1000 || local.IsExecutedFull(person) {
+		t.Fatalf("action applied before completion")
+	}
+
+	person.continueActivity()
+	person.ApplySharedEffects()
+	if person.Activity != nil || person.ActivityGlobal != nil || person.ActivityLocal != nil {
+		t.Fatalf("activity not finished")
+	}
+	if person.Items["diploma"] != 1 || person.Money != 1050 {
+		t.Errorf("items = %v, money = %d after completion", person.Items, person.Money)
+	}
+	if !local.IsExecutedFull(person) || !person.CompletedGlobalTargets[global] || person.GlobalTargets[global] {
+		t.Errorf("targets not completed")
+	}
+}
+
+// Оборванное действие не дает результата
+func TestInterruptedActivityDoesNotApply(t *testing.T) {
+	study := NewAction("study", 100, 2, []string{"education"}, nil, map[string]int64{"diploma": 1}, nil, 0, nil, "")
+	local := NewLocalTarget("learn", []string{"education"}, []*Action{study})
+	global := NewGlobalTarget("career", []string{"education"}, 1, 1, nil, []*LocalTarget{local})
+
+	person := NewHuman(map[*Human]bool{}, nil, nil)
+	person.GlobalTargets[global] = true
+
+	person.startActivity(study, global, local)
+	person.ApplySharedEffects()
+	person.stopActivity()
+	person.continueActivity()
+	person.ApplySharedEffects()
+
+	if person.Items["diploma"] != 0 || local.IsExecutedFull(person) || len(person.CompletedGlobalTargets) != 0 {
+		t.Errorf("interrupted action was applied")
+	}
+}
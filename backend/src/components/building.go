@@ -1,6 +1,8 @@
 package components
 
-import "sync"
+import (
+	"sync"
+)
 
 // BuildingType представляет тип здания
 type BuildingType string
@@ -28,6 +30,10 @@ type Building struct {
 	// Для жилых зданий - содержит жителей
 	Residents map[*Human]bool
 
+	// Для жилых зданий - владельцы квартир (у каждой занятой квартиры один владелец,
+	// остальные жители - члены семей владельцев)
+	Owners map[*Human]bool
+
 	// Общая вместимость и текущая заполненность
 	Capacity int
 	Occupied int
@@ -51,20 +57,11 @@ func NewBuilding(id int, buildingType BuildingType, name string, capacity int, l
 		Location:  location,
 		Jobs:      make(map[*Job]bool),
 		Residents: make(map[*Human]bool),
+		Owners:    make(map[*Human]bool),
 		Capacity:  capacity,
 		Occupied:  0,
 	}
 
-	// Инициализировать цены квартир для жилых зданий
-	if buildingType == ResidentialHouse {
-		// Разные цены для малых и больших городов
-		if location.Name == "City 1" {
-			building.ApartmentPrice = 2000000 // 2 миллиона рублей для малого города
-		} else {
-			building.ApartmentPrice = 3000000 // 3 миллиона рублей для большого города
-		}
-	}
-
 	return building
 }
 
@@ -103,13 +100,23 @@ func (b *Building) AddResident(human *Human) bool {
 	}
 
 	b.Residents[human] = true
+	b.Owners[human] = true
 	b.Occupied++
 	human.ResidentialBuilding = b
 	human.CurrentBuilding = b
 	return true
 }
 
-// SellApartmentToAdmin продает квартиру администрации (мгновенная продажа)
+// IsOwner сообщает, владеет ли человек квартирой в здании
+func (b *Building) IsOwner(human *Human) bool {
+	b.Mu.RLock()
+	defer b.Mu.RUnlock()
+
+	return b.Owners[human]
+}
+
+// SellApartmentToAdmin продает квартиру администрации (мгновенная продажа).
+// Продать квартиру может только ее владелец
 func (b *Building) SellApartmentToAdmin(seller *Human) bool {
 	if b.Type != ResidentialHouse {
 		return false
@@ -118,13 +125,19 @@ func (b *Building) SellApartmentToAdmin(seller *Human) bool {
 	b.Mu.Lock()
 	defer b.Mu.Unlock()
 
+	if !b.Owners[seller] {
+		return false
+	}
+
 	// Удалить жителя из здания
 	delete(b.Residents, seller)
+	delete(b.Owners, seller)
 	b.Occupied--
 	seller.ResidentialBuilding = nil
 
 	// Выплатить деньги за квартиру (администрация покупает по полной стоимости)
 	seller.Money += b.ApartmentPrice
+	recordEvent(EventHousingSale, []*Human{seller}, housingPayload(b))
 
 	return true
 }
@@ -151,13 +164,49 @@ func (b *Building) BuyApartmentFromAdmin(buyer *Human) bool {
 	// Обработать покупку у администрации
 	buyer.Money -= b.ApartmentPrice
 	b.Residents[buyer] = true
+	b.Owners[buyer] = true
 	b.Occupied++
 	buyer.ResidentialBuilding = b
 	buyer.CurrentBuilding = b
+	recordEvent(EventHousingPurchase, []*Human{buyer}, housingPayload(b))
 
 	return true
 }
 
+// Vacate выселяет члена семьи владельца (квартира остается за владельцем).
+// Владелец не выселяется, а продает или передает квартиру
+func (b *Building) Vacate(human *Human) {
+	b.Mu.Lock()
+	defer b.Mu.Unlock()
+
+	delete(b.Residents, human)
+	human.ResidentialBuilding = nil
+}
+
+// JoinFamily заселяет человека в квартиру члена семьи (без проверки вместимости)
+func (b *Building) JoinFamily(human *Human) {
+	b.Mu.Lock()
+	defer b.Mu.Unlock()
+
+	b.Residents[human] = true
+	human.ResidentialBuilding = b
+	human.CurrentBuilding = b
+}
+
+// TransferApartment переоформляет квартиру на другого человека и выписывает прежнего владельца
+// (занятость дома не меняется)
+func (b *Building) TransferApartment(from, to *Human) {
+	b.Mu.Lock()
+	defer b.Mu.Unlock()
+
+	delete(b.Residents, from)
+	delete(b.Owners, from)
+	from.ResidentialBuilding = nil
+	b.Residents[to] = true
+	b.Owners[to] = true
+	to.ResidentialBuilding = b
+}
+
 // SetCoordinates устанавливает координаты здания
 func (b *Building) SetCoordinates(lat, lon float64) {
 	b.Mu.Lock()
@@ -189,12 +238,14 @@ func (b *Building) MoveToSpouse(human *Human, spouse *Human) bool {
 	// Заблокировать текущее здание сначала
 	b.Mu.Lock()
 
-	// Удалить из текущего здания и продать квартиру администрации
-	if b.Residents[human] {
-		delete(b.Residents, human)
+	// Удалить из текущего здания; владелец продает квартиру администрации
+	delete(b.Residents, human)
+	if b.Owners[human] {
+		delete(b.Owners, human)
 		b.Occupied--
 		// Продать квартиру администрации (получить деньги)
 		human.Money += b.ApartmentPrice
+		recordEvent(EventHousingSale, []*Human{human}, housingPayload(b))
 	}
 	b.Mu.Unlock()
 
@@ -209,3 +260,30 @@ func (b *Building) MoveToSpouse(human *Human, spouse *Human) bool {
 
 	return true
 }
+
+// AddVisitor занимает место посетителя в нежилом здании (для выполнения действия)
+func (b *Building) AddVisitor() bool {
+	if b.Type == ResidentialHouse {
+		return false
+	}
+
+	b.Mu.Lock()
+	defer b.Mu.Unlock()
+
+	if b.Occupied >= b.Capacity {
+		return false
+	}
+
+	b.Occupied++
+	return true
+}
+
+// RemoveVisitor освобождает место посетителя
+func (b *Building) RemoveVisitor() {
+	b.Mu.Lock()
+	defer b.Mu.Unlock()
+
+	if b.Occupied > 0 {
+		b.Occupied--
+	}
+}
@@ -0,0 +1,136 @@
+package components
+
+import (
+	"github.com/fallra1n/humanity/src/config"
+	"github.com/fallra1n/humanity/src/utils"
+)
+
+// Детство.
+// Дети не выбирают действий, не работают и не вступают в брак: младенцы остаются дома с родителями,
+// а с SchoolStartAge до AdultAge дети по будням в рабочие часы ходят в школу своего города
+// и занимают в ней место (Occupied). Достигнув AdultAge, ребенок заканчивает школу и, если посетил
+// не меньше MinSchoolAttendance учебных часов, получает GraduationItems.
+// Взрослые дети живут с родителями, пока не накопят на собственную квартиру
+
+// isSchoolAge сообщает, должен ли ребенок ходить в школу
+func (h *Human) isSchoolAge() bool {
+	return h.Age >= config.SchoolStartAge && h.Age < config.AdultAge
+}
+
+// isSchoolHour сообщает, идут ли сейчас уроки (по будням в рабочие часы)
+func isSchoolHour() bool {
+	tick := utils.GlobalTick.Get()
+	return utils.IsWorkTime(tick) && utils.IsWorkDay(tick)
+}
+
+// attendSchool проводит учебный час в школе: занимает место, если ученик еще не в школе.
+// Если в городе нет школы или в ней нет мест, час считается пропущенным.
+// Места в школах общие, поэтому вызывается только в последовательной фазе
+func (h *Human) attendSchool() {
+	if h.Dead {
+		return
+	}
+
+	if !h.AtSchool {
+		// Записаться в школу (заново - после переезда в другой город)
+		if h.School == nil || h.School.Location != h.HomeLocation {
+			h.School = h.chooseSchool()
+		}
+		if h.School == nil || !h.School.AddVisitor() {
+			h.MissedSchoolHours++
+			return
+		}
+		h.AtSchool = true
+	}
+
+	h.CurrentBuilding = h.School
+	h.SchoolHours++
+}
+
+// chooseSchool выбирает случайную школу в городе ребенка
+func (h *Human) chooseSchool() *Building {
+	var schools []*Building
+	for _, building := range GetBuildings(h.HomeLocation) {
+		if building.Type == School {
+			schools = append(schools, building)
+		}
+	}
+	if len(schools) == 0 {
+		return nil
+	}
+	return schools[h.Rand.NextInt(len(schools))]
+}
+
+// leaveSchool освобождает место в школе после уроков
+func (h *Human) leaveSchool() {
+	if !h.AtSchool {
+		return
+	}
+
+	h.School.RemoveVisitor()
+	h.AtSchool = false
+
+	if h.CurrentBuilding == h.School {
+		h.CurrentBuilding = h.ResidentialBuilding
+	}
+}
+
+// comeOfAge заканчивает детство: выпуск из школы и аттестат при достаточной посещаемости
+func (h *Human) comeOfAge() {
+	h.deferShared(func() {
+		h.leaveSchool()
+		h.School = nil
+	})
+
+	total := h.SchoolHours + h.MissedSchoolHours
+	if total == 0 {
+		return
+	}
+
+	attendance := float64(h.SchoolHours) / float64(total)
+	certificate := attendance >= config.MinSchoolAttendance
+	if certificate {
+		for _, item := range config.GraduationItems {
+			h.Items[item]++
+		}
+	}
+
+	h.deferEvent(EventGraduation, []*Human{h}, map[string]interface{}{
+		"school_hours": h.SchoolHours,
+		"attendance":   attendance,
+		"certificate":  certificate,
+	})
+}
+
+// livesWithParents сообщает, живет ли человек в квартире кого-то из родителей
+func (h *Human) livesWithParents() bool {
+	if h.ResidentialBuilding == nil {
+		return false
+	}
+	for parent := range h.Parents {
+		if !parent.Dead && parent.ResidentialBuilding == h.ResidentialBuilding {
+			return true
+		}
+	}
+	return false
+}
+
+// moveOutFromParents покупает взрослому, живущему с родителями, квартиру в другом доме, если хватает денег;
+// съехавший заводит собственное домохозяйство.
+// Вызывается только в последовательной фазе
+func (h *Human) moveOutFromParents() {
+	if h.Age < config.AdultAge || !h.livesWithParents() {
+		return
+	}
+
+	home := h.ResidentialBuilding
+	for _, building := range GetResidentialBuildings(h.HomeLocation) {
+		if building != home && building.BuyApartmentFromAdmin(h) {
+			home.Mu.Lock()
+			delete(home.Residents, h)
+			home.Mu.Unlock()
+			NewHousehold(h)
+			return
+		}
+	}
+}
@@ -2,211 +2,136 @@ package components
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/fallra1n/humanity/src/config"
 	"github.com/fallra1n/humanity/src/utils"
 )
 
-// CreateSmallCity создает малый город с 10 зданиями
-// 1 больница, 1 школа, 2 рабочих места, 1 развлечение, 1 кафе, 1 магазин, 3 жилых дома
-func CreateSmallCity(name string) *Location {
+// buildingLabels - названия типов зданий для автоматических имен
+var buildingLabels = map[BuildingType]string{
+	Hospital:         "Hospital",
+	School:           "School",
+	Workplace:        "Office",
+	Entertainment:    "Entertainment Center",
+	Cafe:             "Cafe",
+	Shop:             "Shop",
+	ResidentialHouse: "House",
+}
+
+// BuildCity создает город по описанию из конфигурации.
+// ID зданий и вакансий назначаются по порядку описания, начиная с 1
+func BuildCity(spec config.CitySpec, rng *utils.Random) *Location {
 	city := &Location{
-		Name:      name,
+		Name:      spec.Name,
 		Buildings: make(map[*Building]bool),
 		Jobs:      make(map[*Job]bool),
 		Humans:    make(map[*Human]bool),
 		Paths:     make(map[*Path]bool),
 	}
 
-	buildingID := 1
-
-	// 1 Больница
-	lat, lon := config.GetCoordinateForBuilding(name, "hospital", 0)
-	hospital := NewBuildingWithCoordinates(buildingID, Hospital, fmt.Sprintf("%s Hospital", name), config.SmallCityHospitalCapacity, city, lat, lon)
-	city.Buildings[hospital] = true
-	buildingID++
-
-	// 1 Школа
-	lat, lon = config.GetCoordinateForBuilding(name, "school", 0)
-	school := NewBuildingWithCoordinates(buildingID, School, fmt.Sprintf("%s School", name), config.SmallCitySchoolCapacity, city, lat, lon)
-	city.Buildings[school] = true
-	buildingID++
-
-	// 2 Рабочих места
-	for i := 1; i <= 2; i++ {
-		lat, lon := config.GetCoordinateForBuilding(name, "workplace", i-1)
-		workplace := NewBuildingWithCoordinates(buildingID, Workplace, fmt.Sprintf("%s Office %d", name, i), config.SmallCityWorkplaceCapacity, city, lat, lon)
-
-		// Создать работы для этого рабочего места
-		job := &Job{
-			VacantPlaces: make(map[*Vacancy]uint64),
-			HomeLocation: city,
-			Building:     workplace,
+	// Сколько зданий каждого типа: единственное здание типа получает имя без номера
+	typeCounts := make(map[BuildingType]int)
+	for _, bs := range spec.Buildings {
+		typeCounts[BuildingType(bs.Type)]++
+	}
+	typeIndex := make(map[BuildingType]int)
+
+	vacancyID := 1
+	for i, bs := range spec.Buildings {
+		buildingType := BuildingType(bs.Type)
+		typeIndex[buildingType]++
+
+		name := bs.Name
+		if name == "" {
+			name = fmt.Sprintf("%s %s", spec.Name, buildingLabels[buildingType])
+			if typeCounts[buildingType] > 1 {
+				name = fmt.Sprintf("%s %d", name, typeIndex[buildingType])
+			}
 		}
 
-		// Создать младшие и старшие позиции
-		salaryRange := config.SmallCityJuniorSalaryMax - config.SmallCityJuniorSalaryMin
-		juniorVacancy := &Vacancy{
-			Parent:       job,
-			RequiredTags: make(map[string]bool),
-			Payment:      config.SmallCityJuniorSalaryMin + utils.GlobalRandom.NextInt(salaryRange),
+		lat, lon := bs.Lat, bs.Lon
+		if lat == 0 && lon == 0 {
+			lat, lon = spec.Center.Lat, spec.Center.Lon
 		}
 
-		salaryRange = config.SmallCitySeniorSalaryMax - config.SmallCitySeniorSalaryMin
-		seniorVacancy := &Vacancy{
-			Parent:       job,
-			RequiredTags: make(map[string]bool),
-			Payment:      config.SmallCitySeniorSalaryMin + utils.GlobalRandom.NextInt(salaryRange),
+		building := NewBuildingWithCoordinates(i+1, buildingType, name, bs.Capacity, city, lat, lon)
+		if buildingType == ResidentialHouse {
+			building.ApartmentPrice = spec.ApartmentPrice
 		}
 
-		// Некоторые старшие позиции требуют образования
-		if i == 1 {
-			seniorVacancy.RequiredTags["engineer_diploma"] = true
+		for _, js := range bs.Jobs {
+			job := &Job{
+				VacantPlaces: make(map[*Vacancy]uint64),
+				HomeLocation: city,
+				Building:     building,
+			}
+
+			// Сначала зарплаты всех вакансий, затем количество позиций
+			vacancies := make([]*Vacancy, len(js.Vacancies))
+			for k, vs := range js.Vacancies {
+				vacancies[k] = &Vacancy{
+					ID:           vacancyID,
+					Parent:       job,
+					RequiredTags: make(map[string]bool),
+					Payment:      vs.Salary.Min + rng.NextInt(vs.Salary.Max-vs.Salary.Min),
+				}
+				for _, tag := range vs.RequiredTags {
+					vacancies[k].RequiredTags[tag] = true
+				}
+				vacancyID++
+			}
+			for k, vs := range js.Vacancies {
+				job.VacantPlaces[vacancies[k]] = uint64(vs.Positions.Min + rng.NextInt(vs.Positions.Max-vs.Positions.Min))
+			}
+
+			building.AddJob(job)
+			city.Jobs[job] = true
 		}
 
-		// Каждая вакансия имеет позиции на основе конфигурации
-		vacancyRange := config.SmallCityVacanciesMax - config.SmallCityVacanciesMin
-		job.VacantPlaces[juniorVacancy] = uint64(config.SmallCityVacanciesMin + utils.GlobalRandom.NextInt(vacancyRange))
-		job.VacantPlaces[seniorVacancy] = uint64(config.SmallCityVacanciesMin + utils.GlobalRandom.NextInt(vacancyRange))
-
-		workplace.AddJob(job)
-		city.Jobs[job] = true
-		city.Buildings[workplace] = true
-		buildingID++
-	}
-
-	// 1 Развлекательный центр
-	lat, lon = config.GetCoordinateForBuilding(name, "entertainment", 0)
-	entertainment := NewBuildingWithCoordinates(buildingID, Entertainment, fmt.Sprintf("%s Entertainment Center", name), config.SmallCityEntertainmentCapacity, city, lat, lon)
-	city.Buildings[entertainment] = true
-	buildingID++
-
-	// 1 Кафе
-	lat, lon = config.GetCoordinateForBuilding(name, "cafe", 0)
-	cafe := NewBuildingWithCoordinates(buildingID, Cafe, fmt.Sprintf("%s Cafe", name), config.SmallCityCafeCapacity, city, lat, lon)
-	city.Buildings[cafe] = true
-	buildingID++
-
-	// 1 Магазин
-	lat, lon = config.GetCoordinateForBuilding(name, "shop", 0)
-	shop := NewBuildingWithCoordinates(buildingID, Shop, fmt.Sprintf("%s Shop", name), config.SmallCityShopCapacity, city, lat, lon)
-	city.Buildings[shop] = true
-	buildingID++
-
-	// 3 Жилых дома
-	for i := 1; i <= 3; i++ {
-		lat, lon := config.GetCoordinateForBuilding(name, "residential_house", i-1)
-		house := NewBuildingWithCoordinates(buildingID, ResidentialHouse, fmt.Sprintf("%s House %d", name, i), config.SmallCityHouseCapacity, city, lat, lon)
-		city.Buildings[house] = true
-		buildingID++
+		city.Buildings[building] = true
 	}
 
 	return city
 }
 
-// CreateLargeCity создает большой город с 15 зданиями
-// 2 больницы, 2 школы, 3 рабочих места, 1 развлечение, 2 кафе, 2 магазина, 3 жилых дома
-func CreateLargeCity(name string) *Location {
-	city := &Location{
-		Name:      name,
-		Buildings: make(map[*Building]bool),
-		Jobs:      make(map[*Job]bool),
-		Humans:    make(map[*Human]bool),
-		Paths:     make(map[*Path]bool),
-	}
-
-	buildingID := 1
-
-	// 2 Больницы
-	for i := 1; i <= 2; i++ {
-		lat, lon := config.GetCoordinateForBuilding(name, "hospital", i-1)
-		hospital := NewBuildingWithCoordinates(buildingID, Hospital, fmt.Sprintf("%s Hospital %d", name, i), config.LargeCityHospitalCapacity, city, lat, lon)
-		city.Buildings[hospital] = true
-		buildingID++
+// BuildCities создает все города по описаниям в заданном порядке
+func BuildCities(specs []config.CitySpec, rng *utils.Random) []*Location {
+	cities := make([]*Location, 0, len(specs))
+	for _, spec := range specs {
+		cities = append(cities, BuildCity(spec, rng))
 	}
+	return cities
+}
 
-	// 2 Школы
-	for i := 1; i <= 2; i++ {
-		lat, lon := config.GetCoordinateForBuilding(name, "school", i-1)
-		school := NewBuildingWithCoordinates(buildingID, School, fmt.Sprintf("%s School %d", name, i), config.LargeCitySchoolCapacity, city, lat, lon)
-		city.Buildings[school] = true
-		buildingID++
+// BuildPaths соединяет города дорогами по описаниям из конфигурации.
+// Дорога принадлежит городу отправления
+func BuildPaths(cities []*Location, specs []config.PathSpec) {
+	byName := make(map[string]*Location, len(cities))
+	for _, city := range cities {
+		byName[city.Name] = city
 	}
 
-	// 3 Рабочих места
-	for i := 1; i <= 3; i++ {
-		lat, lon := config.GetCoordinateForBuilding(name, "workplace", i-1)
-		workplace := NewBuildingWithCoordinates(buildingID, Workplace, fmt.Sprintf("%s Office %d", name, i), config.LargeCityWorkplaceCapacity, city, lat, lon)
-
-		// Создать работы для этого рабочего места
-		job := &Job{
-			VacantPlaces: make(map[*Vacancy]uint64),
-			HomeLocation: city,
-			Building:     workplace,
+	for _, spec := range specs {
+		from, to := byName[spec.From], byName[spec.To]
+		if from == nil || to == nil {
+			continue
 		}
-
-		// Создать младшие и старшие позиции
-		salaryRange := config.LargeCityJuniorSalaryMax - config.LargeCityJuniorSalaryMin
-		juniorVacancy := &Vacancy{
-			Parent:       job,
-			RequiredTags: make(map[string]bool),
-			Payment:      config.LargeCityJuniorSalaryMin + utils.GlobalRandom.NextInt(salaryRange),
-		}
-
-		salaryRange = config.LargeCitySeniorSalaryMax - config.LargeCitySeniorSalaryMin
-		seniorVacancy := &Vacancy{
-			Parent:       job,
-			RequiredTags: make(map[string]bool),
-			Payment:      config.LargeCitySeniorSalaryMin + utils.GlobalRandom.NextInt(salaryRange),
-		}
-
-		// Некоторые старшие позиции требуют образования
-		if i <= 2 {
-			seniorVacancy.RequiredTags["engineer_diploma"] = true
-		}
-
-		// Каждая вакансия имеет позиции на основе конфигурации
-		vacancyRange := config.LargeCityVacanciesMax - config.LargeCityVacanciesMin
-		job.VacantPlaces[juniorVacancy] = uint64(config.LargeCityVacanciesMin + utils.GlobalRandom.NextInt(vacancyRange))
-		job.VacantPlaces[seniorVacancy] = uint64(config.LargeCityVacanciesMin + utils.GlobalRandom.NextInt(vacancyRange))
-
-		workplace.AddJob(job)
-		city.Jobs[job] = true
-		city.Buildings[workplace] = true
-		buildingID++
-	}
-
-	// 1 Развлекательный центр
-	lat, lon := config.GetCoordinateForBuilding(name, "entertainment", 0)
-	entertainment := NewBuildingWithCoordinates(buildingID, Entertainment, fmt.Sprintf("%s Entertainment Center", name), config.LargeCityEntertainmentCapacity, city, lat, lon)
-	city.Buildings[entertainment] = true
-	buildingID++
-
-	// 2 Кафе
-	for i := 1; i <= 2; i++ {
-		lat, lon := config.GetCoordinateForBuilding(name, "cafe", i-1)
-		cafe := NewBuildingWithCoordinates(buildingID, Cafe, fmt.Sprintf("%s Cafe %d", name, i), config.LargeCityCafeCapacity, city, lat, lon)
-		city.Buildings[cafe] = true
-		buildingID++
+		from.Paths[&Path{From: from, To: to, Price: spec.Price, Time: spec.Time}] = true
 	}
+}
 
-	// 2 Магазина
-	for i := 1; i <= 2; i++ {
-		lat, lon := config.GetCoordinateForBuilding(name, "shop", i-1)
-		shop := NewBuildingWithCoordinates(buildingID, Shop, fmt.Sprintf("%s Shop %d", name, i), config.LargeCityShopCapacity, city, lat, lon)
-		city.Buildings[shop] = true
-		buildingID++
-	}
+// GetBuildings возвращает все здания в локации, упорядоченные по ID
+func GetBuildings(location *Location) []*Building {
+	location.Mu.RLock()
+	defer location.Mu.RUnlock()
 
-	// 3 Жилых дома
-	for i := 1; i <= 3; i++ {
-		lat, lon := config.GetCoordinateForBuilding(name, "residential_house", i-1)
-		house := NewBuildingWithCoordinates(buildingID, ResidentialHouse, fmt.Sprintf("%s House %d", name, i), config.LargeCityHouseCapacity, city, lat, lon)
-		city.Buildings[house] = true
-		buildingID++
+	buildings := make([]*Building, 0, len(location.Buildings))
+	for building := range location.Buildings {
+		buildings = append(buildings, building)
 	}
-
-	return city
+	sortBuildings(buildings)
+	return buildings
 }
 
 // GetResidentialBuildings возвращает все жилые здания в локации
@@ -220,6 +145,7 @@ func GetResidentialBuildings(location *Location) []*Building {
 			residential = append(residential, building)
 		}
 	}
+	sortBuildings(residential)
 	return residential
 }
 
@@ -234,6 +160,7 @@ func GetWorkplaceBuildings(location *Location) []*Building {
 			workplaces = append(workplaces, building)
 		}
 	}
+	sortBuildings(workplaces)
 	return workplaces
 }
 
@@ -264,7 +191,7 @@ func CalculateFamilyFriendlyCoefficient(city *Location) float64 {
 	city.Mu.RLock()
 	defer city.Mu.RUnlock()
 
-	coefficient := 1.0 // Базовый коэффициент
+	coefficient := config.BaseFamilyCoefficient // Базовый коэффициент
 
 	buildingCounts := make(map[BuildingType]int)
 	for building := range city.Buildings {
@@ -272,17 +199,17 @@ func CalculateFamilyFriendlyCoefficient(city *Location) float64 {
 	}
 
 	// Больницы увеличивают семейный коэффициент (медицинская помощь детям)
-	coefficient += float64(buildingCounts[Hospital]) * 0.3
+	coefficient += float64(buildingCounts[Hospital]) * config.HospitalFamilyBonus
 
 	// Школы увеличивают семейный коэффициент (образование для детей)
-	coefficient += float64(buildingCounts[School]) * 0.4
+	coefficient += float64(buildingCounts[School]) * config.SchoolFamilyBonus
 
 	// Развлекательные центры увеличивают коэффициент (семейные мероприятия)
-	coefficient += float64(buildingCounts[Entertainment]) * 0.2
+	coefficient += float64(buildingCounts[Entertainment]) * config.EntertainmentFamilyBonus
 
 	// Кафе и магазины обеспечивают удобство для семей
-	coefficient += float64(buildingCounts[Cafe]) * 0.1
-	coefficient += float64(buildingCounts[Shop]) * 0.1
+	coefficient += float64(buildingCounts[Cafe]) * config.CafeFamilyBonus
+	coefficient += float64(buildingCounts[Shop]) * config.ShopFamilyBonus
 
-	return coefficient
+	return math.Min(coefficient, config.MaxFamilyCoefficient)
 }
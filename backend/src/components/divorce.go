@@ -0,0 +1,54 @@
+package components
+
+import (
+	"github.com/fallra1n/humanity/src/config"
+	"github.com/fallra1n/humanity/src/utils"
+)
+
+// ProcessDivorces обрабатывает разводы супружеских пар
+func ProcessDivorces(people []*Human, rng *utils.Random) {
+	for _, person := range people {
+		spouse := person.Spouse
+		if person.Dead || person.MaritalStatus != Married || spouse == nil || spouse.Dead {
+			continue
+		}
+
+		// Каждая пара рассматривается один раз - со стороны супруга с меньшим ID
+		if GlobalHumanStorage.Get(person) > GlobalHumanStorage.Get(spouse) {
+			continue
+		}
+
+		if rng.NextFloat() < person.DivorceProbability() {
+			person.Divorce()
+		}
+	}
+}
+
+// DivorceProbability вычисляет вероятность развода пары в текущий час.
+// Риск растет от денежного стресса и безработицы супругов и снижается с длительностью брака
+func (h *Human) DivorceProbability() float64 {
+	if h.Spouse == nil {
+		return 0
+	}
+
+	probability := config.BaseDivorceRate
+
+	for _, partner := range []*Human{h, h.Spouse} {
+		// Денежный стресс (нехватка денег или потеря работы)
+		for _, splash := range partner.Splashes {
+			if splash.Name == SplashNeedMoney || splash.Name == SplashJobLoss {
+				probability += config.MoneyStressDivorceRate
+				break
+			}
+		}
+
+		// Безработица
+		if partner.Job == nil {
+			probability += config.UnemploymentDivorceRate
+		}
+	}
+
+	// Длительность отношений в годах: чем дольше брак, тем он устойчивее
+	years := h.Family[h.Spouse]
+	return probability / (1 + years/config.DivorceStabilityYears)
+}
@@ -0,0 +1,170 @@
+package components
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Эффекты действий (слова !имя:аргумент:... в actions.ini).
+// Эффект применяется к исполнителю действия в параллельной фазе часа:
+// изменения собственного состояния выполняются сразу, а затрагивающие других людей
+// или общие ресурсы (вакансии, жилье, отношения) откладываются через deferShared
+
+// EffectFunc применяет эффект с разобранными аргументами к исполнителю действия
+type EffectFunc func(person *Human, args []string)
+
+// effectDefinition - зарегистрированный эффект
+type effectDefinition struct {
+	minArgs, maxArgs int // допустимое количество аргументов (maxArgs < 0 - без ограничения)
+	validate         func(args []string) error
+	apply            EffectFunc
+}
+
+// effectRegistry - таблица известных эффектов
+var effectRegistry = map[string]effectDefinition{}
+
+// RegisterEffect регистрирует эффект. validate проверяет аргументы при загрузке действий (может быть nil)
+func RegisterEffect(name string, minArgs, maxArgs int, validate func(args []string) error, apply EffectFunc) {
+	effectRegistry[name] = effectDefinition{minArgs: minArgs, maxArgs: maxArgs, validate: validate, apply: apply}
+}
+
+func init() {
+	// !splash:имя:часы[:тег...] - всплеск (теги по умолчанию - имя всплеска)
+	RegisterEffect("splash", 2, -1, validateSplash, func(person *Human, args []string) {
+		lifetime, _ := strconv.ParseUint(args[1], 10, 64)
+		tags := args[2:]
+		if len(tags) == 0 {
+			tags = args[:1]
+		}
+		person.Splashes = append(person.Splashes, NewSplash(args[0], tags, lifetime))
+	})
+
+	// !friends:дельта - изменить силу отношений со всеми друзьями
+	RegisterEffect("friends", 1, 1, validateFloatArg, func(person *Human, args []string) {
+		delta, _ := strconv.ParseFloat(args[0], 64)
+		person.deferShared(func() {
+			for _, friend := range sortedHumans(person.Friends) {
+				person.Friends[friend] += delta
+				if _, ok := friend.Friends[person]; ok {
+					friend.Friends[person] += delta
+				}
+			}
+		})
+	})
+
+	// !spouse:дельта - изменить силу отношений с супругом (Family хранит длительность брака и не меняется)
+	RegisterEffect("spouse", 1, 1, validateFloatArg, func(person *Human, args []string) {
+		delta, _ := strconv.ParseFloat(args[0], 64)
+		person.deferShared(func() {
+			spouse := person.Spouse
+			if spouse == nil || spouse.Spouse != person {
+				return
+			}
+			person.SpouseBond += delta
+			spouse.SpouseBond += delta
+		})
+	})
+
+	// !goto:тип_здания - пойти в здание этого типа в своем городе (до следующей смены места).
+	// Посетитель занимает место в здании, поэтому это делается в последовательной фазе.
+	// В больницу можно зайти ненадолго (навестить больного): это не госпитализация, лечения и оплаты нет
+	RegisterEffect("goto", 1, 1, validateVisitBuildingType, func(person *Human, args []string) {
+		buildingType := BuildingType(args[0])
+		person.deferShared(func() { person.visitBuilding(buildingType) })
+	})
+
+	// !buy_housing - купить квартиру, если своего жилья нет
+	RegisterEffect("buy_housing", 0, 0, nil, func(person *Human, args []string) {
+		person.deferShared(func() {
+			if person.ResidentialBuilding == nil {
+				person.findHousing()
+			}
+		})
+	})
+
+	// !find_job - искать работу (вакансии общие)
+	RegisterEffect("find_job", 0, 0, nil, func(person *Human, args []string) {
+		person.deferShared(func() { findJob(person) })
+	})
+
+	// !migrate - обдумать переезд в другой город
+	RegisterEffect("migrate", 0, 0, nil, func(person *Human, args []string) {
+		person.deferShared(func() {
+			if path, vacancy := person.chooseMigration(); path != nil {
+				person.Migrate(path, vacancy)
+			}
+		})
+	})
+
+	// !set:атрибут:значение - установить атрибут человека (читается в правилах как attr(атрибут))
+	RegisterEffect("set", 2, 2, func(args []string) error {
+		return validateFloatArg(args[1:])
+	}, func(person *Human, args []string) {
+		value, _ := strconv.ParseFloat(args[1], 64)
+		person.Attributes[args[0]] = value
+	})
+}
+
+// Effect - эффект действия с аргументами
+type Effect struct {
+	Name string
+	Args []string
+	def  effectDefinition
+}
+
+// ParseEffect разбирает эффект вида имя:аргумент:... (без префикса !)
+func ParseEffect(source string) (*Effect, error) {
+	parts := strings.Split(source, ":")
+	name, args := parts[0], parts[1:]
+
+	def, ok := effectRegistry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown effect %q", name)
+	}
+	if len(args) < def.minArgs || def.maxArgs >= 0 && len(args) > def.maxArgs {
+		return nil, fmt.Errorf("effect %s: wrong number of arguments: %d", name, len(args))
+	}
+	for _, arg := range args {
+		if arg == "" {
+			return nil, fmt.Errorf("effect %s: empty argument", name)
+		}
+	}
+	if def.validate != nil {
+		if err := def.validate(args); err != nil {
+			return nil, fmt.Errorf("effect %s: %v", name, err)
+		}
+	}
+
+	return &Effect{Name: name, Args: args, def: def}, nil
+}
+
+// Apply применяет эффект к исполнителю действия
+func (e *Effect) Apply(person *Human) {
+	e.def.apply(person, e.Args)
+}
+
+// String возвращает эффект в записи actions.ini (без префикса !)
+func (e *Effect) String() string {
+	return strings.Join(append([]string{e.Name}, e.Args...), ":")
+}
+
+func validateSplash(args []string) error {
+	if _, err := strconv.ParseUint(args[1], 10, 64); err != nil {
+		return fmt.Errorf("invalid lifetime %q", args[1])
+	}
+	return nil
+}
+
+func validateFloatArg(args []string) error {
+	if _, err := strconv.ParseFloat(args[0], 64); err != nil {
+		return fmt.Errorf("invalid number %q", args[0])
+	}
+	return nil
+}
+
+// validateVisitBuildingType проверяет тип здания для !goto
+func validateVisitBuildingType(args []string) error {
+	_, err := ParseActivityBuildingType(args[0])
+	return err
+}
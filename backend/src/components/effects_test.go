@@ -0,0 +1,84 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fallra1n/humanity/src/config"
+	"github.com/fallra1n/humanity/src/utils"
+)
+
+func TestParseEffect(t *testing.T) {
+	tests := []struct {
+		source string
+		err    string // фрагмент текста ошибки (пусто - эффект корректен)
+	}{
+		{"splash:joy:24", ""},
+		{"splash:joy:24:fun:rest", ""},
+		{"splash:joy", "wrong number of arguments: 1"},
+		{"splash:joy:-1", `invalid lifetime "-1"`},
+		{"splash:joy:day", `invalid lifetime "day"`},
+		{"friends:0.5", ""},
+		{"friends:-1", ""},
+		{"friends", "wrong number of arguments: 0"},
+		{"friends:1:2", "wrong number of arguments: 2"},
+		{"friends:much", `invalid number "much"`},
+		{"spouse:0.1", ""},
+		{"spouse:", "empty argument"},
+		{"goto:cafe", ""},
+		{"goto:hospital", ""},
+		{"goto:residential_house", "actions cannot take place in residential_house"},
+		{"goto:castle", `unknown building type "castle"`},
+		{"buy_housing", ""},
+		{"buy_housing:now", "wrong number of arguments: 1"},
+		{"find_job", ""},
+		{"migrate", ""},
+		{"set:mood:0.5", ""},
+		{"set:mood", "wrong number of arguments: 1"},
+		{"set:mood:high", `invalid number "high"`},
+		{"fly", `unknown effect "fly"`},
+	}
+
+	for _, tt := range tests {
+		effect, err := ParseEffect(tt.source)
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("ParseEffect(%q): %v", tt.source, err)
+		case tt.err == "" && effect.String() != tt.source:
+			t.Errorf("ParseEffect(%q).String() = %q", tt.source, effect.String())
+		case tt.err != "" && err == nil:
+			t.Errorf("ParseEffect(%q): expected error containing %q", tt.source, tt.err)
+		case tt.err != "" && !strings.Contains(err.Error(), tt.err):
+			t.Errorf("ParseEffect(%q) = %q, want error containing %q", tt.source, err, tt.err)
+		}
+	}
+}
+
+// Посещение больницы по !goto занимает место, но не кладет человека в больницу
+func TestGotoHospitalIsVisit(t *testing.T) {
+	city := BuildCities(config.Cities, utils.NewRandom(1))[0]
+	person := NewHuman(map[*Human]bool{}, city, nil)
+
+	effect, err := ParseEffect("goto:hospital")
+	if err != nil {
+		t.Fatal(err)
+	}
+	effect.Apply(person)
+	person.ApplySharedEffects()
+
+	building := person.ActivityBuilding
+	if building == nil || building.Type != Hospital {
+		t.Fatalf("person is not visiting a hospital")
+	}
+	if person.Hospital != nil || person.HospitalHours != 0 {
+		t.Errorf("visit admitted the person to the hospital")
+	}
+	if person.CurrentBuilding != building || building.Occupied != 1 {
+		t.Errorf("visitor is not counted in the hospital (occupied %d)", building.Occupied)
+	}
+
+	person.stopActivity()
+	if person.ActivityBuilding != nil || building.Occupied != 0 {
+		t.Errorf("visit did not release the place (occupied %d)", building.Occupied)
+	}
+}
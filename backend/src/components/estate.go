@@ -0,0 +1,161 @@
+package components
+
+import (
+	"sort"
+
+	"github.com/fallra1n/humanity/src/config"
+)
+
+// Наследство.
+// После смерти имущество умершего делят поровну живые наследники первой непустой очереди
+// из config.HeirOrder (по умолчанию супруг, затем дети, затем родители).
+// Квартира остается родственникам, которые в ней живут (переоформляется на одного из них);
+// иначе она переходит наследнику без жилья
+// из того же города, а если такого нет - продается администрации, и деньги входят в наследство.
+// Личные предметы (config.NonTransferableItems) не наследуются, остальные делятся между наследниками.
+// Долги (отрицательный баланс) переходят наследникам только при config.InheritDebts, иначе списываются;
+// квартира с долгами не переходит наследнику, а продается в счет долга.
+// Если наследников нет, имущество отходит администрации.
+// Итог раздела записывается в событие inheritance
+
+// Исходы раздела квартиры
+const (
+	ApartmentKept        = "kept"        // в квартире остаются родственники
+	ApartmentTransferred = "transferred" // переоформлена на наследника
+	ApartmentSold        = "sold"        // продана администрации, деньги вошли в наследство
+	ApartmentReleased    = "released"    // наследников нет, квартира вернулась администрации
+)
+
+// heirs возвращает первую непустую очередь живых наследников и ее имя
+func (h *Human) heirs() (string, []*Human) {
+	for _, group := range config.HeirOrder {
+		var candidates []*Human
+		switch group {
+		case "spouse":
+			if h.MaritalStatus == Married && h.Spouse != nil && !h.Spouse.Dead {
+				candidates = append(candidates, h.Spouse)
+			}
+		case "children":
+			candidates = livingHumans(h.Children)
+		case "parents":
+			candidates = livingHumans(h.Parents)
+		}
+		if len(candidates) > 0 {
+			return group, candidates
+		}
+	}
+	return "", nil
+}
+
+// livingHumans возвращает живых людей из связей в детерминированном порядке
+func livingHumans(m map[*Human]float64) []*Human {
+	var living []*Human
+	for _, human := range sortedHumans(m) {
+		if !human.Dead {
+			living = append(living, human)
+		}
+	}
+	return living
+}
+
+// settleEstate делит имущество умершего между наследниками и выписывает его из квартиры.
+// Вызывается только в последовательной фазе, пока связи умершего с семьей еще не разорваны
+func (h *Human) settleEstate() {
+	group, heirs := h.heirs()
+
+	apartment := h.settleApartment(heirs)
+
+	estate := h.Money
+	h.Money = 0
+	var writtenOff int64
+	switch {
+	case len(heirs) > 0 && (estate > 0 || config.InheritDebts):
+		share := estate / int64(len(heirs))
+		for _, heir := range heirs {
+			heir.Money += share
+		}
+		// Остаток от деления достается первому наследнику, чтобы деньги не пропадали
+		heirs[0].Money += estate - share*int64(len(heirs))
+	case estate < 0:
+		writtenOff = -estate
+	}
+
+	items := h.passItems(heirs)
+
+	if len(heirs) == 0 && estate == 0 && apartment == "" {
+		return
+	}
+	recordEvent(EventInheritance, append([]*Human{h}, heirs...), map[string]interface{}{
+		"heirs":            group,
+		"money":            estate,
+		"debt_written_off": writtenOff,
+		"items":            items,
+		"apartment":        apartment,
+	})
+}
+
+// settleApartment решает судьбу квартиры умершего и возвращает исход (пусто, если своей квартиры не было)
+func (h *Human) settleApartment(heirs []*Human) string {
+	home := h.ResidentialBuilding
+	if home == nil {
+		return ""
+	}
+	if !home.IsOwner(h) {
+		h.releaseHousing()
+		return ""
+	}
+
+	if h.hasRelativesIn(home) {
+		h.releaseHousing()
+		return ApartmentKept
+	}
+
+	if h.Money >= 0 || config.InheritDebts {
+		for _, heir := range heirs {
+			if heir.ResidentialBuilding == nil && heir.HomeLocation == home.Location {
+				home.TransferApartment(h, heir)
+				return ApartmentTransferred
+			}
+		}
+	}
+
+	if len(heirs) > 0 {
+		home.SellApartmentToAdmin(h)
+		return ApartmentSold
+	}
+
+	h.releaseHousing()
+	return ApartmentReleased
+}
+
+// passItems делит передаваемые предметы между наследниками по очереди и возвращает их число
+func (h *Human) passItems(heirs []*Human) int64 {
+	if len(heirs) == 0 {
+		return 0
+	}
+
+	nonTransferable := make(map[string]bool)
+	for _, item := range config.NonTransferableItems {
+		nonTransferable[item] = true
+	}
+
+	names := make([]string, 0, len(h.Items))
+	for item := range h.Items {
+		if !nonTransferable[item] {
+			names = append(names, item)
+		}
+	}
+	sort.Strings(names)
+
+	var passed int64
+	next := 0
+	for _, item := range names {
+		for count := h.Items[item]; count > 0; count-- {
+			heirs[next].Items[item]++
+			next = (next + 1) % len(heirs)
+			passed++
+		}
+		delete(h.Items, item)
+	}
+	return passed
+}
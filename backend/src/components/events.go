@@ -0,0 +1,97 @@
+package components
+
+import "github.com/fallra1n/humanity/src/utils"
+
+// Типы событий
+const (
+	EventMarriage          = "marriage"
+	EventDivorce           = "divorce"
+	EventBirth             = "birth"
+	EventDeath             = "death"
+	EventHire              = "hire"
+	EventQuit              = "quit"
+	EventFire              = "fire"
+	EventAction            = "action"
+	EventTargetCompleted   = "target_completed"
+	EventTargetAssigned    = "target_assigned"
+	EventTargetAbandoned   = "target_abandoned"
+	EventHousingPurchase   = "housing_purchase"
+	EventHousingSale       = "housing_sale"
+	EventMigration         = "migration"
+	EventGraduation        = "graduation"
+	EventIllness           = "illness"
+	EventRecovery          = "recovery"
+	EventHospitalAdmission = "hospital_admission"
+	EventInheritance       = "inheritance"
+	EventGuardianship      = "guardianship"
+)
+
+// Event описывает одно изменение в мире: что произошло, когда и с кем
+type Event struct {
+	Tick    uint64                 `json:"tick"`
+	Type    string                 `json:"type"`
+	Agents  []int                  `json:"agents"` // ID людей из GlobalHumanStorage, первый - инициатор
+	Payload map[string]interface{} `json:"payload,omitempty"`
+}
+
+// EventRecorder принимает события симуляции.
+// Вызывается только из последовательной фазы часа, поэтому порядок событий детерминирован
+type EventRecorder interface {
+	Record(event Event)
+}
+
+// eventRecorder - текущий получатель событий (nil - события не записываются)
+var eventRecorder EventRecorder
+
+// SetEventRecorder устанавливает получателя событий
+func SetEventRecorder(recorder EventRecorder) {
+	eventRecorder = recorder
+}
+
+// recordEvent записывает событие. Допустим только вне параллельной фазы часа
+func recordEvent(eventType string, agents []*Human, payload map[string]interface{}) {
+	if eventRecorder == nil {
+		return
+	}
+
+	ids := make([]int, 0, len(agents))
+	for _, agent := range agents {
+		ids = append(ids, GlobalHumanStorage.Get(agent))
+	}
+
+	eventRecorder.Record(Event{
+		Tick:    utils.GlobalTick.Get(),
+		Type:    eventType,
+		Agents:  ids,
+		Payload: payload,
+	})
+}
+
+// deferEvent откладывает запись события, произошедшего в параллельной фазе,
+// до последовательной фазы часа
+func (h *Human) deferEvent(eventType string, agents []*Human, payload map[string]interface{}) {
+	if eventRecorder == nil {
+		return
+	}
+	h.deferShared(func() { recordEvent(eventType, agents, payload) })
+}
+
+// vacancyPayload описывает вакансию для событий найма и увольнения
+func vacancyPayload(vacancy *Vacancy) map[string]interface{} {
+	building := vacancy.Parent.Building
+	return map[string]interface{}{
+		"city":     building.Location.Name,
+		"building": building.Name,
+		"vacancy":  vacancy.ID,
+		"salary":   vacancy.Payment,
+	}
+}
+
+// housingPayload описывает квартиру для событий покупки и продажи жилья
+func housingPayload(building *Building) map[string]interface{} {
+	return map[string]interface{}{
+		"city":     building.Location.Name,
+		"building": building.Name,
+		"price":    building.ApartmentPrice,
+	}
+}
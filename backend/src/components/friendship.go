@@ -5,19 +5,24 @@ import (
 )
 
 // ProcessFriendships обрабатывает формирование дружбы между людьми в одном здании
-func ProcessFriendships(people []*Human) {
-	// Группировать людей по их текущему зданию
+func ProcessFriendships(people []*Human, rng *utils.Random) {
+	// Группировать людей по их текущему зданию (в порядке первого появления здания)
 	buildingGroups := make(map[*Building][]*Human)
+	var buildingOrder []*Building
 
 	for _, person := range people {
 		if person.Dead || person.CurrentBuilding == nil {
 			continue
 		}
+		if _, exists := buildingGroups[person.CurrentBuilding]; !exists {
+			buildingOrder = append(buildingOrder, person.CurrentBuilding)
+		}
 		buildingGroups[person.CurrentBuilding] = append(buildingGroups[person.CurrentBuilding], person)
 	}
 
 	// Обработать дружбу в каждом здании
-	for _, group := range buildingGroups {
+	for _, building := range buildingOrder {
+		group := buildingGroups[building]
 		if len(group) < 2 {
 			continue // Нужно как минимум 2 человека для формирования дружбы
 		}
@@ -34,7 +39,7 @@ func ProcessFriendships(people []*Human) {
 				}
 
 				// 25% шанс стать друзьями
-				if utils.GlobalRandom.NextFloat() < 0.25 {
+				if rng.NextFloat() < 0.25 {
 					// Создать двустороннюю дружбу
 					person1.Friends[person2] = 0.0 // Начать с 0 силы отношений
 					person2.Friends[person1] = 0.0
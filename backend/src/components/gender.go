@@ -9,10 +9,11 @@ const (
 	Female Gender = "female"
 )
 
-func (g Gender) GetDeathAge() float64 {
+// MortalityTable возвращает таблицу смертности пола
+func (g Gender) MortalityTable() config.MortalityTable {
 	if g == Male {
-		return config.DeathAgeMale
+		return config.MaleMortality
 	}
 
-	return config.DeathAgeFemale
+	return config.FemaleMortality
 }
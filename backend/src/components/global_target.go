@@ -10,18 +10,26 @@ type GlobalTarget struct {
 	Name            string
 	Tags            map[string]bool
 	Power           float64
+	Weight          float64            // Относительная частота, с которой цель выпадает при назначении
+	Stages          map[LifeStage]bool // Этапы жизни, на которых цель назначается (пусто - на любых)
 	TargetsPossible map[*LocalTarget]bool
 	TargetsExecuted map[*LocalTarget]bool
+	LastProgress    uint64 // Час последнего выполненного ради цели действия (в персональной копии)
 	Mu              sync.RWMutex
 }
 
 // NewGlobalTarget создает новую глобальную цель
-func NewGlobalTarget(name string, tags []string, power float64, allTargets []*LocalTarget) *GlobalTarget {
+func NewGlobalTarget(name string, tags []string, power, weight float64, stages []LifeStage, allTargets []*LocalTarget) *GlobalTarget {
 	tagSet := make(map[string]bool)
 	for _, tag := range tags {
 		tagSet[tag] = true
 	}
 
+	stageSet := make(map[LifeStage]bool)
+	for _, stage := range stages {
+		stageSet[stage] = true
+	}
+
 	targetsPossible := make(map[*LocalTarget]bool)
 	for _, target := range allTargets {
 		if len(utils.IntersectSlices(tags, getKeysFromMap(target.Tags))) > 0 {
@@ -33,6 +41,8 @@ func NewGlobalTarget(name string, tags []string, power float64, allTargets []*Lo
 		Name:            name,
 		Tags:            tagSet,
 		Power:           power,
+		Weight:          weight,
+		Stages:          stageSet,
 		TargetsPossible: targetsPossible,
 		TargetsExecuted: make(map[*LocalTarget]bool),
 	}
@@ -65,6 +75,27 @@ func (gt *GlobalTarget) IsExecutedFull() bool {
 	return len(remainingTags) == 0
 }
 
+// Progress возвращает долю тегов цели, покрытых выполненными локальными целями
+func (gt *GlobalTarget) Progress() float64 {
+	gt.Mu.RLock()
+	defer gt.Mu.RUnlock()
+
+	if len(gt.Tags) == 0 {
+		return 1
+	}
+
+	covered := make(map[string]bool)
+	for target := range gt.TargetsExecuted {
+		for tag := range target.Tags {
+			if gt.Tags[tag] {
+				covered[tag] = true
+			}
+		}
+	}
+
+	return float64(len(covered)) / float64(len(gt.Tags))
+}
+
 // Executable проверяет, может ли глобальная цель быть выполнена
 func (gt *GlobalTarget) Executable(person *Human) bool {
 	gt.Mu.RLock()
@@ -134,7 +165,8 @@ func (gt *GlobalTarget) ChooseTarget(person *Human) *LocalTarget {
 
 		candidates := rating[maxRate]
 		if len(candidates) > 0 {
-			return candidates[utils.GlobalRandom.NextInt(len(candidates))]
+			sortLocalTargets(candidates)
+			return candidates[person.Rand.NextInt(len(candidates))]
 		}
 	}
 
@@ -0,0 +1,247 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/fallra1n/humanity/src/config"
+	"github.com/fallra1n/humanity/src/utils"
+)
+
+// Назначение глобальных целей в течение жизни.
+// Набор доступных целей зависит от этапа жизни: у детей, взрослых, родителей и пенсионеров он разный
+// (этапы цели задаются в global.ini словами ~этап). Цель выпадает с вероятностью, пропорциональной
+// ее весу (*вес). Человек отказывается от целей, которые не подходят его этапу жизни или стали
+// невыполнимыми, и получает новые, когда целей остается меньше MinGlobalTargets.
+// Невыполнимая цель не назначается снова, пока не пройдет GoalPatienceHours часов после отказа.
+// Выполненная цель тоже назначается снова не раньше, чем через GoalPatienceHours часов,
+// если только все остальные цели этапа не заняты - тогда лучше повторить ее раньше, чем остаться без целей
+
+// LifeStage - этап жизни человека
+type LifeStage string
+
+const (
+	ChildStage      LifeStage = "child"       // младше AdultAge
+	YoungAdultStage LifeStage = "young_adult" // взрослый без несовершеннолетних детей
+	ParentStage     LifeStage = "parent"      // взрослый с несовершеннолетними детьми
+	RetireeStage    LifeStage = "retiree"     // старше RetirementAge
+)
+
+// LifeStages - все этапы жизни в порядке взросления
+var LifeStages = []LifeStage{ChildStage, YoungAdultStage, ParentStage, RetireeStage}
+
+// Причины отказа от цели
+const (
+	AbandonLifeStage  = "life_stage" // цель не подходит новому этапу жизни
+	AbandonImpossible = "impossible" // цель давно не продвигается и не может быть выполнена
+)
+
+// ParseLifeStage проверяет название этапа жизни (слово ~этап в global.ini)
+func ParseLifeStage(name string) (LifeStage, error) {
+	for _, stage := range LifeStages {
+		if LifeStage(name) == stage {
+			return stage, nil
+		}
+	}
+	return "", fmt.Errorf("unknown life stage %q", name)
+}
+
+// LifeStage определяет текущий этап жизни человека
+func (h *Human) LifeStage() LifeStage {
+	switch {
+	case h.Age < config.AdultAge:
+		return ChildStage
+	case h.Age >= config.RetirementAge:
+		return RetireeStage
+	}
+
+	for child := range h.Children {
+		if !child.Dead && child.Age < config.AdultAge {
+			return ParentStage
+		}
+	}
+	return YoungAdultStage
+}
+
+// AllowedFor сообщает, назначается ли цель на этом этапе жизни
+func (gt *GlobalTarget) AllowedFor(stage LifeStage) bool {
+	return len(gt.Stages) == 0 || gt.Stages[stage]
+}
+
+// Copy создает персональную копию определения цели для человека
+func (gt *GlobalTarget) Copy() *GlobalTarget {
+	gt.Mu.RLock()
+	defer gt.Mu.RUnlock()
+
+	target := &GlobalTarget{
+		Name:            gt.Name,
+		Tags:            make(map[string]bool),
+		Power:           gt.Power,
+		Weight:          gt.Weight,
+		Stages:          gt.Stages, // не меняется после загрузки
+		TargetsPossible: make(map[*LocalTarget]bool),
+		TargetsExecuted: make(map[*LocalTarget]bool),
+		LastProgress:    utils.GlobalTick.Get(),
+	}
+
+	for tag := range gt.Tags {
+		target.Tags[tag] = true
+	}
+	for localTarget := range gt.TargetsPossible {
+		target.TargetsPossible[localTarget] = true
+	}
+
+	return target
+}
+
+// assignGlobalTargets добирает человеку глобальные цели его этапа жизни
+// до случайного количества от MinGlobalTargets до MaxGlobalTargets (не включая).
+// Возвращает назначенные цели
+func (h *Human) assignGlobalTargets(definitions []*GlobalTarget) []*GlobalTarget {
+	stage := h.LifeStage()
+	count := config.MinGlobalTargets + h.Rand.NextInt(config.MaxGlobalTargets-config.MinGlobalTargets)
+
+	var assigned []*GlobalTarget
+	for len(h.GlobalTargets) < count {
+		candidates, totalWeight := h.goalCandidates(definitions, stage)
+		if len(candidates) == 0 {
+			break
+		}
+
+		// Выбор с вероятностью, пропорциональной весу
+		chosen := candidates[len(candidates)-1]
+		threshold := h.Rand.NextFloat() * totalWeight
+		for _, candidate := range candidates {
+			threshold -= candidate.Weight
+			if threshold < 0 {
+				chosen = candidate
+				break
+			}
+		}
+
+		target := chosen.Copy()
+		if h.hasCompletedGoal(target.Name) {
+			// Повторную цель нужно выполнить заново, а не закрыть прежним продвижением
+			for local := range target.TargetsPossible {
+				delete(h.LocalProgress, local)
+			}
+		}
+		h.GlobalTargets[target] = true
+		assigned = append(assigned, target)
+	}
+
+	return assigned
+}
+
+// goalCandidates возвращает цели этапа жизни, которых у человека нет, от которых он недавно
+// не отказался как от невыполнимых и которые он недавно не выполнил.
+// Если недавно выполнены все оставшиеся цели этапа, выполненные цели снова становятся кандидатами
+func (h *Human) goalCandidates(definitions []*GlobalTarget, stage LifeStage) ([]*GlobalTarget, float64) {
+	taken := make(map[string]bool)
+	for target := range h.GlobalTargets {
+		taken[target.Name] = true
+	}
+	for name := range h.AbandonedGoals {
+		taken[name] = true
+	}
+
+	// Время выполнения цели - ее последнее продвижение
+	tick := utils.GlobalTick.Get()
+	recent := make(map[string]bool)
+	for target := range h.CompletedGlobalTargets {
+		if tick-target.LastProgress < config.GoalPatienceHours {
+			recent[target.Name] = true
+		}
+	}
+
+	candidates, totalWeight := stageGoals(definitions, stage, taken, recent)
+	if len(candidates) == 0 {
+		candidates, totalWeight = stageGoals(definitions, stage, taken, nil)
+	}
+	return candidates, totalWeight
+}
+
+// hasCompletedGoal сообщает, выполнял ли человек цель с этим именем
+func (h *Human) hasCompletedGoal(name string) bool {
+	for target := range h.CompletedGlobalTargets {
+		if target.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// stageGoals возвращает цели этапа жизни с положительным весом, кроме исключенных, и их суммарный вес
+func stageGoals(definitions []*GlobalTarget, stage LifeStage, taken, recent map[string]bool) ([]*GlobalTarget, float64) {
+	var candidates []*GlobalTarget
+	totalWeight := 0.0
+	for _, definition := range definitions {
+		if taken[definition.Name] || recent[definition.Name] || definition.Weight <= 0 || !definition.AllowedFor(stage) {
+			continue
+		}
+		candidates = append(candidates, definition)
+		totalWeight += definition.Weight
+	}
+	return candidates, totalWeight
+}
+
+// ReviewGoals пересматривает глобальные цели живых людей.
+// Вызывается в последовательной фазе часа, после рождений
+func ReviewGoals(people []*Human, definitions []*GlobalTarget) {
+	for _, person := range people {
+		if !person.Dead {
+			person.reviewGoals(definitions)
+		}
+	}
+}
+
+// reviewGoals отказывается от неподходящих целей и назначает новые, если целей осталось мало
+func (h *Human) reviewGoals(definitions []*GlobalTarget) {
+	stage := h.LifeStage()
+	tick := utils.GlobalTick.Get()
+
+	// Истекшие отказы снова допускают цель к назначению
+	for name, abandoned := range h.AbandonedGoals {
+		if tick-abandoned >= config.GoalPatienceHours {
+			delete(h.AbandonedGoals, name)
+		}
+	}
+
+	targets := make([]*GlobalTarget, 0, len(h.GlobalTargets))
+	for target := range h.GlobalTargets {
+		targets = append(targets, target)
+	}
+	sortGlobalTargets(targets)
+
+	for _, target := range targets {
+		var reason string
+		if !target.AllowedFor(stage) {
+			reason = AbandonLifeStage
+		} else if h.isGoalStalled(target) {
+			reason = AbandonImpossible
+			h.AbandonedGoals[target.Name] = tick
+		} else {
+			continue
+		}
+
+		delete(h.GlobalTargets, target)
+		recordEvent(EventTargetAbandoned, []*Human{h}, map[string]interface{}{
+			"target": target.Name,
+			"reason": reason,
+		})
+	}
+
+	if len(h.GlobalTargets) >= config.MinGlobalTargets {
+		return
+	}
+	for _, target := range h.assignGlobalTargets(definitions) {
+		recordEvent(EventTargetAssigned, []*Human{h}, map[string]interface{}{
+			"target": target.Name,
+			"stage":  string(stage),
+		})
+	}
+}
+
+// isGoalStalled сообщает, что цель не продвигалась GoalPatienceHours часов и сейчас невыполнима
+func (h *Human) isGoalStalled(target *GlobalTarget) bool {
+	return utils.GlobalTick.Get()-target.LastProgress >= config.GoalPatienceHours && !target.Executable(h)
+}
@@ -0,0 +1,79 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fallra1n/humanity/src/config"
+	"github.com/fallra1n/humanity/src/utils"
+)
+
+func TestGoalCandidatesAfterCompletion(t *testing.T) {
+	defer utils.GlobalTick.Set(0)
+	utils.GlobalTick.Set(config.GoalPatienceHours)
+
+	career := NewGlobalTarget("career", []string{"career"}, 1, 1, nil, nil)
+	travel := NewGlobalTarget("travel", []string{"travel"}, 1, 1, nil, nil)
+	family := NewGlobalTarget("family", []string{"family"}, 1, 1, []LifeStage{ParentStage}, nil)
+	definitions := []*GlobalTarget{career, travel, family}
+
+	person := NewHuman(map[*Human]bool{}, nil, nil)
+	person.Age = config.AdultAge + 10
+
+	names := func() string {
+		t.Helper()
+		candidates, _ := person.goalCandidates(definitions, person.LifeStage())
+		var names []string
+		for _, candidate := range candidates {
+			names = append(names, candidate.Name)
+		}
+		return strings.Join(names, ",")
+	}
+
+	// Только что выполненная цель уступает другим целям этапа
+	completed := career.Copy()
+	person.CompletedGlobalTargets[completed] = true
+	if got := names(); got != "travel" {
+		t.Errorf("after completion: candidates = %s, want travel", got)
+	}
+
+	// Других целей этапа нет - выполненная цель назначается раньше срока
+	person.GlobalTargets[travel.Copy()] = true
+	if got := names(); got != "career" {
+		t.Errorf("all other goals taken: candidates = %s, want career", got)
+	}
+
+	// Недавний отказ как от невыполнимой не снимается
+	person.AbandonedGoals["career"] = utils.GlobalTick.Get()
+	if got := names(); got != "" {
+		t.Errorf("abandoned goal: candidates = %s, want none", got)
+	}
+	delete(person.AbandonedGoals, "career")
+
+	// После GoalPatienceHours выполненная цель снова обычный кандидат
+	person.GlobalTargets = make(map[*GlobalTarget]bool)
+	utils.GlobalTick.Set(completed.LastProgress + config.GoalPatienceHours)
+	if got := names(); got != "career,travel" {
+		t.Errorf("after patience: candidates = %s, want career,travel", got)
+	}
+}
+
+// Повторно назначенную выполненную цель нельзя закрыть прежним продвижением по локальным целям
+func TestRepeatedGoalStartsOver(t *testing.T) {
+	gym := NewAction("gym", 0, 0, []string{"sport"}, nil, nil, nil, 0, nil, "")
+	local := NewLocalTarget("train", []string{"sport"}, []*Action{gym})
+	sport := NewGlobalTarget("sport", []string{"sport"}, 1, 1, nil, []*LocalTarget{local})
+
+	person := NewHuman(map[*Human]bool{}, nil, nil)
+	person.Age = config.AdultAge + 10
+	local.MarkAsExecuted(person, gym)
+	person.CompletedGlobalTargets[sport.Copy()] = true
+
+	assigned := person.assignGlobalTargets([]*GlobalTarget{sport})
+	if len(assigned) != 1 || assigned[0].Name != "sport" {
+		t.Fatalf("completed goal was not assigned again")
+	}
+	if local.IsExecutedFull(person) {
+		t.Errorf("repeated goal kept the old local progress")
+	}
+}
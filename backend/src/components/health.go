@@ -0,0 +1,222 @@
+package components
+
+import (
+	"math"
+
+	"github.com/fallra1n/humanity/src/config"
+	"github.com/fallra1n/humanity/src/utils"
+)
+
+// Здоровье.
+// Здоровье человека (от 0 до 1) восстанавливается до базового уровня, который снижается с возрастом,
+// и падает от стресса. Человек заболевает болезнями из сценария (health.illnesses) тем чаще,
+// чем хуже его здоровье; болезнь отнимает здоровье и повышает риск смерти от болезней.
+// Больной не ходит на работу (берет больничный) и в школу и не выполняет действия.
+// Тяжелые болезни лечат в больнице: там болезнь проходит быстрее и менее опасна, но каждый день
+// стоит HospitalDayCost, а больной занимает место (Occupied). В больнице же проходят роды
+
+// Причины госпитализации
+const (
+	AdmissionIllness    = "illness"
+	AdmissionChildbirth = "childbirth"
+)
+
+// IsSick сообщает, болеет ли человек
+func (h *Human) IsSick() bool {
+	return h.Illness != ""
+}
+
+// InBed сообщает, что человек болеет или лежит в больнице и не выполняет действий
+func (h *Human) InBed() bool {
+	return h.IsSick() || h.Hospital != nil
+}
+
+// healthBaseline возвращает уровень, до которого восстанавливается здоровье в этом возрасте
+func healthBaseline(age float64) float64 {
+	return math.Max(0, 1-config.HealthAgingDecay*math.Max(0, age-config.AdultAge))
+}
+
+// illnessSpec ищет описание болезни по имени
+func illnessSpec(name string) *config.IllnessSpec {
+	for i := range config.Illnesses {
+		if config.Illnesses[i].Name == name {
+			return &config.Illnesses[i]
+		}
+	}
+	return nil
+}
+
+// updateHealth проводит час жизни здоровья: течение болезни, заражение, восстановление и стресс
+func (h *Human) updateHealth() {
+	if h.IsSick() {
+		h.progressIllness()
+	} else {
+		h.catchIllness()
+	}
+
+	baseline := healthBaseline(h.Age)
+	if h.Health > baseline {
+		h.Health = baseline
+	} else if !h.IsSick() {
+		h.Health = math.Min(baseline, h.Health+config.HealthRecoveryRate/config.HoursPerYear)
+	}
+
+	stress := h.splashStrength(map[string]bool{config.HealthStressTag: true})
+	h.Health = math.Max(0, h.Health-config.HealthStressDamage*stress/config.HoursPerYear)
+
+	if h.Hospital != nil {
+		// Выписка после выздоровления, а после родов - не раньше MaternityStayHours
+		if h.HospitalHours > 0 {
+			h.HospitalHours--
+		}
+		if h.HospitalHours == 0 && !h.IsSick() {
+			h.deferShared(h.leaveHospital)
+		}
+		if utils.GlobalTick.Get()%config.HoursPerDay == 0 {
+			h.deferShared(h.payHospitalDay)
+		}
+	}
+
+	// Больничные считаются за год работы
+	if h.Job != nil && h.JobTime%config.HoursPerYear == 0 {
+		h.SickLeaveHours = 0
+	}
+}
+
+// catchIllness разыгрывает заболевание; чем хуже здоровье, тем чаще человек болеет
+func (h *Human) catchIllness() {
+	multiplier := 1 + (config.IllnessHealthFactor-1)*(1-h.Health)
+	rates := make([]float64, len(config.Illnesses))
+	for i, illness := range config.Illnesses {
+		rates[i] = illness.Rate * math.Exp(illness.AgeGrowth*h.Age) * multiplier
+	}
+
+	i := h.rollHazards(rates)
+	if i < 0 {
+		return
+	}
+
+	illness := config.Illnesses[i]
+	h.Illness = illness.Name
+	h.IllnessHours = illness.DurationHours
+	h.Health = math.Max(0, h.Health-illness.Severity)
+	h.deferEvent(EventIllness, []*Human{h}, map[string]interface{}{
+		"illness": illness.Name,
+		"health":  h.Health,
+	})
+}
+
+// progressIllness проводит час болезни: больничный, лечение и выздоровление
+func (h *Human) progressIllness() {
+	if h.isWorkingHour() {
+		h.SickLeaveHours++
+	}
+
+	step := uint64(1)
+	if h.Hospital != nil {
+		step = config.HospitalRecoverySpeed
+	}
+	if h.IllnessHours > step {
+		h.IllnessHours -= step
+
+		spec := illnessSpec(h.Illness)
+		if spec != nil && spec.Hospital && h.Hospital == nil {
+			h.deferShared(func() { h.admitToHospital(AdmissionIllness, 0) })
+		}
+		return
+	}
+
+	h.deferEvent(EventRecovery, []*Human{h}, map[string]interface{}{"illness": h.Illness})
+	h.Illness = ""
+	h.IllnessHours = 0
+}
+
+// illnessMortalityFactor возвращает множитель риска смерти от болезней:
+// плохое здоровье и текущая болезнь его повышают, лечение в больнице - снижает
+func (h *Human) illnessMortalityFactor() float64 {
+	factor := 1 + (config.MortalityHealthFactor-1)*(1-h.Health)
+	if spec := illnessSpec(h.Illness); spec != nil {
+		factor *= spec.Mortality
+		if h.Hospital != nil {
+			factor *= config.HospitalMortalityFactor
+		}
+	}
+	return factor
+}
+
+// admitToHospital кладет человека в больницу его города на hours часов (0 - до выздоровления).
+// Больного лечат, только если есть чем заплатить за день; на роды кладут в любом случае.
+// Возвращает больницу или nil, если мест нет. Вызывается только в последовательной фазе
+func (h *Human) admitToHospital(reason string, hours uint64) *Building {
+	if h.Dead {
+		return nil
+	}
+	if h.Hospital != nil {
+		h.HospitalHours = max(h.HospitalHours, hours)
+		return h.Hospital
+	}
+	if reason == AdmissionIllness && h.hospitalPayer().Money < config.HospitalDayCost {
+		return nil
+	}
+
+	var hospitals []*Building
+	for _, building := range GetBuildings(h.HomeLocation) {
+		if building.Type == Hospital {
+			hospitals = append(hospitals, building)
+		}
+	}
+	hospital := h.enterActivityBuilding(hospitals)
+	if hospital == nil {
+		return nil
+	}
+
+	// Начатое действие прерывается до выписки
+	h.leaveActivityBuilding()
+	h.leaveSchool()
+
+	h.Hospital = hospital
+	h.HospitalHours = hours
+	h.CurrentBuilding = hospital
+	h.payHospitalDay()
+
+	recordEvent(EventHospitalAdmission, []*Human{h}, map[string]interface{}{
+		"reason":   reason,
+		"city":     hospital.Location.Name,
+		"building": hospital.Name,
+	})
+	return hospital
+}
+
+// leaveHospital выписывает человека из больницы. Вызывается только в последовательной фазе
+func (h *Human) leaveHospital() {
+	if h.Hospital == nil {
+		return
+	}
+
+	h.Hospital.RemoveVisitor()
+	if h.CurrentBuilding == h.Hospital {
+		h.CurrentBuilding = h.ResidentialBuilding
+	}
+	h.Hospital = nil
+	h.HospitalHours = 0
+}
+
+// payHospitalDay оплачивает день в больнице; за детей платят родители
+func (h *Human) payHospitalDay() {
+	if h.Hospital == nil || h.Dead {
+		return
+	}
+	h.hospitalPayer().Money -= config.HospitalDayCost
+}
+
+// hospitalPayer возвращает того, кто платит за лечение: сам взрослый или живой родитель ребенка
+func (h *Human) hospitalPayer() *Human {
+	if h.Age < config.AdultAge {
+		for _, parent := range sortedHumans(h.Parents) {
+			if !parent.Dead {
+				return parent
+			}
+		}
+	}
+	return h
+}
@@ -0,0 +1,317 @@
+package components
+
+import (
+	"sort"
+
+	"github.com/fallra1n/humanity/src/config"
+	"github.com/fallra1n/humanity/src/utils"
+)
+
+// Домохозяйства.
+// Каждый живой человек состоит в домохозяйстве: у него есть члены, общее жилье и общий бюджет.
+// В день зарплаты работающие члены отдают в бюджет долю зарплаты (config.HouseholdContributionShare),
+// остальное остается их личными деньгами. Бюджет ежедневно оплачивает расходы: DailyExpenses на каждого
+// взрослого и ChildExpensesPerDay на каждого несовершеннолетнего (один раз, а не каждому из родителей).
+// Недостачу бюджета поровну покрывают взрослые из личных денег, а тем, чьи личные деньги ушли в минус,
+// бюджет возмещает долг, пока в нем есть деньги.
+// Дети, в домохозяйстве которых не осталось взрослых, переходят к опекуну - живому родителю,
+// бабушке или дедушке, взрослому брату или сестре; расходы сирот без опекуна сверх бюджета
+// берет на себя государство (бюджет не уходит в минус).
+// Состав меняют брак (переезжающий супруг приходит со своими несовершеннолетними детьми),
+// рождение, развод (уходящий из общего жилья супруг забирает половину бюджета), переезд от родителей, переезд в другой город, опека и смерть.
+// Домохозяйства обрабатываются в последовательной фазе часа (ProcessHouseholds)
+
+// Household - домохозяйство
+type Household struct {
+	ID            int
+	Members       map[*Human]bool
+	Residence     *Building // общее жилье (nil - без жилья)
+	Budget        int64     // общий бюджет в рублях
+	Income        int64     // зарплаты членов за последний месяц
+	Expenses      int64     // расходы за последний месяц
+	MonthExpenses int64     // расходы с начала текущего месяца
+}
+
+// householdCount - число созданных домохозяйств (ID последнего)
+var householdCount int
+
+// HouseholdCount возвращает число созданных домохозяйств
+func HouseholdCount() int {
+	return householdCount
+}
+
+// RestoreHouseholdCount восстанавливает счетчик домохозяйств из контрольной точки
+func RestoreHouseholdCount(count int) {
+	householdCount = count
+}
+
+// NewHousehold создает домохозяйство с одним членом, который уходит из прежнего.
+// Вызывается только в последовательной фазе
+func NewHousehold(founder *Human) *Household {
+	householdCount++
+	household := &Household{
+		ID:        householdCount,
+		Members:   make(map[*Human]bool),
+		Residence: founder.ResidentialBuilding,
+	}
+	founder.joinHousehold(household)
+	return household
+}
+
+// Households возвращает домохозяйства людей в порядке появления их членов в списке
+func Households(people []*Human) []*Household {
+	seen := make(map[*Household]bool)
+	var households []*Household
+	for _, person := range people {
+		if household := person.Household; household != nil && !seen[household] {
+			seen[household] = true
+			households = append(households, household)
+		}
+	}
+	return households
+}
+
+// ProcessHouseholds ведет хозяйство за прошедший час: расходы, зарплаты и покрытие долгов членов
+func ProcessHouseholds(people []*Human) {
+	tick := utils.GlobalTick.Get()
+	for _, household := range Households(people) {
+		household.placeOrphans()
+		household.updateResidence()
+		if tick%config.HoursPerDay == 0 {
+			household.payExpenses()
+		}
+		if tick%config.HoursPerMonth == 0 {
+			household.payday()
+		}
+		household.coverDebts()
+	}
+}
+
+// SortedMembers возвращает членов домохозяйства, упорядоченных по ID
+func (hh *Household) SortedMembers() []*Human {
+	members := make([]*Human, 0, len(hh.Members))
+	for member := range hh.Members {
+		members = append(members, member)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return GlobalHumanStorage.Get(members[i]) < GlobalHumanStorage.Get(members[j])
+	})
+	return members
+}
+
+// MonthlyIncome возвращает сумму зарплат работающих членов
+func (hh *Household) MonthlyIncome() int64 {
+	income := int64(0)
+	for member := range hh.Members {
+		if member.Job != nil {
+			income += int64(member.Job.Payment)
+		}
+	}
+	return income
+}
+
+// updateResidence переносит общее жилье в квартиру первого члена, если в прежней никто из членов не живет
+func (hh *Household) updateResidence() {
+	members := hh.SortedMembers()
+	for _, member := range members {
+		if hh.Residence != nil && member.ResidentialBuilding == hh.Residence {
+			return
+		}
+	}
+	hh.Residence = nil
+	for _, member := range members {
+		if member.ResidentialBuilding != nil {
+			hh.Residence = member.ResidentialBuilding
+			return
+		}
+	}
+}
+
+// payExpenses оплачивает дневные расходы; недостачу поровну покрывают взрослые из личных денег
+func (hh *Household) payExpenses() {
+	var adults []*Human
+	expenses := int64(0)
+	for _, member := range hh.SortedMembers() {
+		if member.Age >= config.AdultAge {
+			adults = append(adults, member)
+			expenses += int64(config.DailyExpenses)
+		} else {
+			expenses += config.ChildExpensesPerDay
+		}
+	}
+
+	hh.Budget -= expenses
+	hh.MonthExpenses += expenses
+
+	if hh.Budget < 0 && len(adults) > 0 {
+		shortfall := -hh.Budget
+		share := shortfall / int64(len(adults))
+		for _, adult := range adults {
+			adult.Money -= share
+		}
+		adults[0].Money -= shortfall - share*int64(len(adults))
+	}
+	hh.Budget = max(hh.Budget, 0)
+}
+
+// placeOrphans переводит детей из домохозяйства без взрослых к опекунам
+func (hh *Household) placeOrphans() {
+	members := hh.SortedMembers()
+	for _, member := range members {
+		if member.Age >= config.AdultAge {
+			return
+		}
+	}
+
+	for _, child := range members {
+		if guardian := child.guardian(); guardian != nil {
+			child.moveInWith(guardian)
+		}
+	}
+}
+
+// guardian возвращает опекуна для ребенка, оставшегося без взрослых в домохозяйстве:
+// живого взрослого родителя, затем бабушку или дедушку, затем взрослого брата или сестру (nil - опекуна нет).
+// Опекун из другого города должен иметь жилье, куда переедет ребенок
+func (h *Human) guardian() *Human {
+	parents := sortedHumans(h.Parents)
+	candidates := append([]*Human(nil), parents...)
+	for _, parent := range parents {
+		candidates = append(candidates, sortedHumans(parent.Parents)...)
+	}
+	for _, parent := range parents {
+		candidates = append(candidates, sortedHumans(parent.Children)...)
+	}
+
+	for _, candidate := range candidates {
+		if candidate == h || candidate.Dead || candidate.Age < config.AdultAge || candidate.Household == nil {
+			continue
+		}
+		if candidate.HomeLocation == h.HomeLocation || candidate.ResidentialBuilding != nil {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// moveInWith переводит ребенка в домохозяйство и жилье опекуна (при необходимости - в его город).
+// Собственная квартира ребенка продается администрации, деньги остаются ему
+func (h *Human) moveInWith(guardian *Human) {
+	h.joinHousehold(guardian.Household)
+
+	if city := guardian.HomeLocation; city != nil && city != h.HomeLocation {
+		h.leaveSchool()
+		h.leaveHospital()
+		h.relocate(city)
+	}
+
+	if home := guardian.ResidentialBuilding; home != nil && h.ResidentialBuilding != home {
+		if old := h.ResidentialBuilding; old != nil {
+			if old.IsOwner(h) {
+				old.SellApartmentToAdmin(h)
+			} else {
+				old.Vacate(h)
+			}
+		}
+		home.JoinFamily(h)
+	}
+	if h.Hospital == nil {
+		h.CurrentBuilding = h.ResidentialBuilding
+	}
+
+	recordEvent(EventGuardianship, []*Human{h, guardian}, map[string]interface{}{
+		"city": guardian.HomeLocation.Name,
+	})
+}
+
+// payday выплачивает месячные зарплаты: доля идет в бюджет, остальное - лично работнику
+func (hh *Household) payday() {
+	hh.Income = 0
+	for _, member := range hh.SortedMembers() {
+		if member.Job == nil {
+			continue
+		}
+		payment := int64(member.Job.Payment)
+		contribution := int64(float64(payment) * config.HouseholdContributionShare)
+		member.Money += payment - contribution
+		hh.Budget += contribution
+		hh.Income += payment
+	}
+
+	hh.Expenses = hh.MonthExpenses
+	hh.MonthExpenses = 0
+}
+
+// coverDebts возмещает из бюджета долги членов, чьи личные деньги ушли в минус
+func (hh *Household) coverDebts() {
+	for _, member := range hh.SortedMembers() {
+		if hh.Budget <= 0 {
+			return
+		}
+		if member.Money < 0 {
+			transfer := min(-member.Money, hh.Budget)
+			member.Money += transfer
+			hh.Budget -= transfer
+		}
+	}
+}
+
+// joinHousehold переводит человека в домохозяйство.
+// Последний член, уходящий из домохозяйства, забирает его бюджет с собой
+func (h *Human) joinHousehold(household *Household) {
+	old := h.Household
+	if old == household {
+		return
+	}
+	if old != nil {
+		delete(old.Members, h)
+		if len(old.Members) == 0 {
+			household.Budget += old.Budget
+			old.Budget = 0
+		}
+	}
+	household.Members[h] = true
+	h.Household = household
+}
+
+// moveToHousehold переводит человека в домохозяйство вместе с его несовершеннолетними детьми из прежнего
+func (h *Human) moveToHousehold(household *Household) {
+	old := h.Household
+	var children []*Human
+	for _, child := range sortedHumans(h.Children) {
+		if !child.Dead && child.Age < config.AdultAge && old != nil && child.Household == old {
+			children = append(children, child)
+		}
+	}
+
+	h.joinHousehold(household)
+	for _, child := range children {
+		child.joinHousehold(household)
+	}
+}
+
+// leaveHousehold выводит умершего из домохозяйства; бюджет опустевшего домохозяйства входит в наследство
+func (h *Human) leaveHousehold() {
+	old := h.Household
+	if old == nil {
+		return
+	}
+	delete(old.Members, h)
+	if len(old.Members) == 0 {
+		h.Money += old.Budget
+		old.Budget = 0
+	}
+	h.Household = nil
+}
+
+// splitHousehold выделяет человеку собственное домохозяйство и передает ему долю share прежнего бюджета
+func (h *Human) splitHousehold(share float64) *Household {
+	old := h.Household
+	household := NewHousehold(h)
+	if old != nil && old != household && len(old.Members) > 0 {
+		part := int64(float64(old.Budget) * share)
+		old.Budget -= part
+		household.Budget += part
+	}
+	return household
+}
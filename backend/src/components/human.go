@@ -15,18 +15,36 @@ type Human struct {
 	Age                    float64
 	Gender                 Gender
 	MaritalStatus          MaritalStatus
-	Spouse                 *Human // Ссылка на супруга, если женат/замужем
-	IsPregnant             bool   // True если в данный момент беременна
-	PregnancyTime          uint64 // Часы с начала беременности
+	Spouse                 *Human  // Ссылка на супруга, если женат/замужем
+	DivorceCount           int     // Количество разводов
+	SpouseBond             float64 // Сила отношений с супругом (эффект !spouse), в браке с нуля
+	IsPregnant             bool    // True если в данный момент беременна
+	PregnancyTime          uint64  // Часы с начала беременности
 	Dead                   bool
+	DeathCause             DeathCause // Причина смерти (пусто у живых)
 	BusyHours              uint64
+	Activity               *Action       // Выполняемое действие (занимает BusyHours часов)
+	ActivityGlobal         *GlobalTarget // Глобальная цель, ради которой выполняется действие
+	ActivityLocal          *LocalTarget  // Локальная цель, ради которой выполняется действие
+	ActivityBuilding       *Building     // Здание, в котором выполняется действие
 	Money                  int64
 	Job                    *Vacancy
 	JobTime                uint64
+	UnemployedTime         uint64 // Часы без работы подряд
 	HomeLocation           *Location
 	CurrentBuilding        *Building // Где человек находится в данный момент
 	WorkBuilding           *Building // Где человек работает (может быть nil если безработный)
 	ResidentialBuilding    *Building
+	School                 *Building // Школа, в которую записан ребенок
+	AtSchool               bool      // Ребенок сейчас занимает место в школе
+	SchoolHours            uint64    // Посещенные учебные часы
+	MissedSchoolHours      uint64    // Пропущенные учебные часы (нет школы или свободных мест)
+	Health                 float64   // Здоровье от 0 до 1
+	Illness                string    // Текущая болезнь (пусто - здоров)
+	IllnessHours           uint64    // Сколько часов еще длится болезнь
+	Hospital               *Building // Больница, в которой человек лежит
+	HospitalHours          uint64    // Сколько часов еще лежать в больнице после родов
+	SickLeaveHours         uint64    // Рабочие часы на больничном за текущий год работы
 	Parents                map[*Human]float64
 	Family                 map[*Human]float64
 	Children               map[*Human]float64
@@ -34,7 +52,19 @@ type Human struct {
 	Splashes               []*Splash
 	GlobalTargets          map[*GlobalTarget]bool
 	CompletedGlobalTargets map[*GlobalTarget]bool
+	AbandonedGoals         map[string]uint64                     // Невыполнимые цели, от которых человек отказался -> час отказа
+	LocalProgress          map[*LocalTarget]*LocalTargetProgress // Продвижение по локальным целям
 	Items                  map[string]int64
+	Attributes             map[string]float64 // Произвольные атрибуты, задаваемые эффектами действий
+
+	// Домохозяйство, в котором человек живет и ведет общий бюджет (nil у умерших)
+	Household *Household
+
+	// Собственный поток случайных чисел, выведенный из зерна симуляции и ID человека
+	Rand *utils.Random
+
+	// Операции над общим состоянием, отложенные до последовательной фазы часа
+	pending []func()
 
 	// Мьютекс для потокобезопасного доступа к отношениям
 	Mu sync.RWMutex
@@ -42,27 +72,14 @@ type Human struct {
 
 // NewHuman создает нового человека
 func NewHuman(parents map[*Human]bool, homeLocation *Location, globalTargets []*GlobalTarget) *Human {
-	// Генерация возраста с нормальным распределением
-	age := math.Max(config.MinAge, math.Min(config.MaxAge, utils.GlobalRandom.NextNormal(config.MeanAge, config.AgeStdDev)))
-
-	// Случайное назначение пола
-	var gender Gender
-	if utils.GlobalRandom.NextFloat() < config.MaleGenderProbability {
-		gender = Male
-	} else {
-		gender = Female
-	}
-
 	human := &Human{
-		Age:                    age,
-		Gender:                 gender,
 		MaritalStatus:          Single, // Начинаем как одинокий
 		Spouse:                 nil,    // Изначально без супруга
 		IsPregnant:             false,  // Изначально не беременна
 		PregnancyTime:          0,      // Нет времени беременности
 		Dead:                   false,
 		BusyHours:              0,
-		Money:                  7000,
+		Money:                  config.StartingMoney,
 		Job:                    nil,
 		JobTime:                720,
 		HomeLocation:           homeLocation,
@@ -75,7 +92,26 @@ func NewHuman(parents map[*Human]bool, homeLocation *Location, globalTargets []*
 		Splashes:               make([]*Splash, 0),
 		GlobalTargets:          make(map[*GlobalTarget]bool),
 		CompletedGlobalTargets: make(map[*GlobalTarget]bool),
+		AbandonedGoals:         make(map[string]uint64),
+		LocalProgress:          make(map[*LocalTarget]*LocalTargetProgress),
 		Items:                  make(map[string]int64),
+		Attributes:             make(map[string]float64),
+	}
+
+	// Зарегистрировать человека и получить его собственный поток случайных чисел
+	GlobalHumanStorage.Append(human)
+	human.Rand = utils.GlobalRandom.Derive(GlobalHumanStorage.Get(human))
+
+	// Генерация возраста с нормальным распределением
+	human.Age = math.Max(config.MinAge, math.Min(config.MaxAge, human.Rand.NextNormal(config.MeanAge, config.AgeStdDev)))
+
+	human.Health = healthBaseline(human.Age)
+
+	// Случайное назначение пола
+	if human.Rand.NextFloat() < config.MaleGenderProbability {
+		human.Gender = Male
+	} else {
+		human.Gender = Female
 	}
 
 	// Установить родителей
@@ -83,49 +119,32 @@ func NewHuman(parents map[*Human]bool, homeLocation *Location, globalTargets []*
 		human.Parents[parent] = 0.0
 	}
 
-	// Назначить случайные глобальные цели
-	numTargets := 2 + utils.GlobalRandom.NextInt(2) // 2-3 цели
-	if numTargets > len(globalTargets) {
-		numTargets = len(globalTargets)
-	}
+	// Назначить глобальные цели этапа жизни
+	human.assignGlobalTargets(globalTargets)
 
-	selectedTargets := make(map[string]bool)
-	for len(human.GlobalTargets) < numTargets {
-		target := globalTargets[utils.GlobalRandom.NextInt(len(globalTargets))]
-		if !selectedTargets[target.Name] {
-			// Создать копию глобальной цели для этого человека
-			newTarget := &GlobalTarget{
-				Name:            target.Name,
-				Tags:            make(map[string]bool),
-				Power:           target.Power,
-				TargetsPossible: make(map[*LocalTarget]bool),
-				TargetsExecuted: make(map[*LocalTarget]bool),
-			}
-
-			// Копировать теги
-			for tag := range target.Tags {
-				newTarget.Tags[tag] = true
-			}
+	return human
+}
 
-			// Копировать возможные цели
-			for localTarget := range target.TargetsPossible {
-				newTarget.TargetsPossible[localTarget] = true
-			}
+// deferShared откладывает операцию, затрагивающую других людей или общие ресурсы,
+// до последовательной фазы часа, чтобы результат не зависел от планирования горутин
+func (h *Human) deferShared(op func()) {
+	h.pending = append(h.pending, op)
+}
 
-			human.GlobalTargets[newTarget] = true
-			selectedTargets[target.Name] = true
-		}
+// ApplySharedEffects выполняет отложенные операции в порядке их постановки.
+// Вызывается последовательно для всех людей после параллельной фазы
+func (h *Human) ApplySharedEffects() {
+	ops := h.pending
+	h.pending = nil
+	for _, op := range ops {
+		op()
 	}
-
-	GlobalHumanStorage.Append(human)
-	return human
 }
 
 // IterateHour обрабатывает один час жизни человека
 func (h *Human) IterateHour() {
 	if h.Money <= 0 {
-		splash := NewSplash("need_money", []string{"money", "well-being", "career"}, 24)
-		h.Splashes = append(h.Splashes, splash)
+		h.EmitSplash(SplashNeedMoney)
 	}
 
 	// Старение отношений
@@ -145,8 +164,10 @@ func (h *Human) IterateHour() {
 	// Управление рабочим временем
 	if h.Job == nil {
 		h.JobTime = 721
+		h.UnemployedTime++
 	} else {
 		h.JobTime++
+		h.UnemployedTime = 0
 	}
 
 	// Удалить истекшие всплески
@@ -159,12 +180,12 @@ func (h *Human) IterateHour() {
 	h.Splashes = validSplashes
 
 	// Обработка смерти
-	if h.Age > h.Gender.GetDeathAge() {
-		if !h.Dead {
-			h.redistributeWealth()
-			h.Money = 0
+	if !h.Dead {
+		if cause := h.rollDeath(); cause != "" {
+			h.DeathCause = cause
+			h.deferShared(h.die)
+			h.Dead = true
 		}
-		h.Dead = true
 	}
 
 	if h.Dead {
@@ -172,22 +193,16 @@ func (h *Human) IterateHour() {
 	}
 
 	// Старение человека
+	wasChild := h.Age < config.AdultAge
 	h.Age += 1.0 / (24 * 365)
+	if wasChild && h.Age >= config.AdultAge {
+		h.comeOfAge()
+	}
 
-	// Ежедневные расходы
-	if utils.GlobalTick.Get()%24 == 0 {
-		dailyExpenses := int64(config.DailyExpenses)
-
-		// Дополнительные расходы на детей
-		dailyExpenses += int64(len(h.Children)) * config.ChildExpensesPerDay
-
-		h.Money -= dailyExpenses
+	// Болезни и лечение
+	h.updateHealth()
 
-		// Месячная зарплата
-		if h.Job != nil && utils.GlobalTick.Get()%(30*24) == 0 {
-			h.Money += int64(h.Job.Payment)
-		}
-	}
+	// Расходы и зарплаты ведет домохозяйство (см. ProcessHouseholds)
 
 	// Обработка беременности и планирования детей (только для женщин)
 	if h.Gender == Female {
@@ -199,27 +214,47 @@ func (h *Human) IterateHour() {
 		// Это будет обработано в main.go для добавления ребенка в список людей
 	}
 
-	// Перераспределить деньги в семье при необходимости
-	if h.Money < 0 {
-		h.redistributeMoneyInFamily()
+	// Проверить рынок труда на лучшие возможности (вакансии общие для всех; дети не работают)
+	if h.Age >= config.AdultAge {
+		h.deferShared(h.checkJobMarket)
 	}
 
-	// Проверить рынок труда на лучшие возможности
-	h.checkJobMarket()
+	// Взрослые без жилья раз в сутки пытаются купить квартиру, взрослые дети - съехать от родителей
+	if h.Age >= config.AdultAge && utils.GlobalTick.Get()%24 == 0 {
+		if h.ResidentialBuilding == nil {
+			h.deferShared(h.findHousing)
+		} else if len(h.Parents) > 0 {
+			h.deferShared(h.moveOutFromParents)
+		}
+	}
 
 	// Обработка перемещения между зданиями
 	h.handleMovement()
 
 	// Обработка дружбы перенесена в main.go для потокобезопасности
 
+	// Больные и лежащие в больнице не выполняют действий
+	if h.InBed() {
+		h.interruptActivity()
+		return
+	}
+
 	// Основная логика активности - проверить, время ли сна
 	if utils.IsSleepTime(utils.GlobalTick.Get()) {
 		// Во время сна (23:00 до 07:00), люди не выполняют действия
 		// Они просто отдыхают и восстанавливаются
+		h.interruptActivity()
 		return
 	}
 
-	if h.BusyHours > 0 {
+	// Дети не выбирают действий
+	if h.Age < config.AdultAge {
+		return
+	}
+
+	if h.Activity != nil {
+		h.continueActivity()
+	} else if h.BusyHours > 0 {
 		h.BusyHours--
 	} else {
 		h.performActions()
@@ -231,14 +266,14 @@ func (h *Human) checkJobMarket() {
 	// Проверять рынок труда чаще и с меньшими требованиями к опыту
 	if h.Job == nil {
 		// Если безработный, искать работу не каждый час - каждые 24 часа для поддержания уровня безработицы
-		if utils.GlobalTick.Get()%24 == 0 {
+		if utils.GlobalTick.Get()%config.UnemployedJobSearchInterval == 0 {
 			findJob(h)
 		}
 		return
 	}
 
 	// Если трудоустроен, проверять лучшие возможности каждую неделю (168 часов)
-	if h.JobTime < 168 || h.JobTime%168 != 0 {
+	if h.JobTime < config.MinJobExperienceForSwitch || h.JobTime%config.EmployedJobSearchInterval != 0 {
 		return
 	}
 
@@ -254,7 +289,7 @@ func (h *Human) checkJobMarket() {
 				job.Mu.RLock()
 				for vacancy, count := range job.VacantPlaces {
 					// Искать работы с умеренным повышением зарплаты (10% или больше)
-					minSalaryIncrease := int(float64(currentSalary) * 1.10)
+					minSalaryIncrease := int(float64(currentSalary) * config.MinSalaryIncreasePercent)
 					if count > 0 && vacancy.Payment >= minSalaryIncrease {
 						// Сбалансированные требования для смены работы
 						requiredSkills := 0
@@ -268,7 +303,7 @@ func (h *Human) checkJobMarket() {
 						}
 
 						// Принять работу если имеет как минимум 70% требуемых навыков или нет требований
-						if requiredSkills == 0 || float64(hasSkills)/float64(requiredSkills) >= 0.7 {
+						if requiredSkills == 0 || float64(hasSkills)/float64(requiredSkills) >= config.MinSkillMatchForSwitch {
 							betterJobs = append(betterJobs, vacancy)
 						}
 					}
@@ -282,6 +317,7 @@ func (h *Human) checkJobMarket() {
 
 	// Рассмотреть смену работы с умеренной вероятностью
 	if len(betterJobs) > 0 {
+		sortVacancies(betterJobs)
 		bestJob := betterJobs[0]
 		for _, job := range betterJobs {
 			if job.Payment > bestJob.Payment {
@@ -291,9 +327,13 @@ func (h *Human) checkJobMarket() {
 
 		// Умеренная вероятность смены работы (20-60% шанс)
 		salaryIncrease := float64(bestJob.Payment-currentSalary) / float64(currentSalary)
-		changeProb := math.Max(0.2, math.Min(0.6, salaryIncrease)) // 20-60% chance
+		changeProb := math.Max(config.MinJobChangeProbability, math.Min(config.MaxJobChangeProbability, salaryIncrease)) // 20-60% chance
+
+		if h.Rand.NextFloat() < changeProb {
+			quit := vacancyPayload(h.Job)
+			quit["reason"] = "job_switch"
+			recordEvent(EventQuit, []*Human{h}, quit)
 
-		if utils.GlobalRandom.NextFloat() < changeProb {
 			// Уволиться с текущей работы
 			h.Job.Parent.Mu.Lock()
 			h.Job.Parent.VacantPlaces[h.Job]++
@@ -304,11 +344,12 @@ func (h *Human) checkJobMarket() {
 			h.Job = bestJob
 			bestJob.Parent.VacantPlaces[bestJob]--
 			h.JobTime = 0 // Сбросить опыт работы
+			h.SickLeaveHours = 0
 			bestJob.Parent.Mu.Unlock()
+			recordEvent(EventHire, []*Human{h}, vacancyPayload(bestJob))
 
 			// Добавить всплеск о карьерном росте
-			splash := NewSplash("career_advancement", []string{"career", "money", "well-being"}, 48)
-			h.Splashes = append(h.Splashes, splash)
+			h.EmitSplash(SplashCareerAdvancement)
 		}
 	}
 }
@@ -319,6 +360,10 @@ func (h *Human) FireEmployee(reason string) {
 		return
 	}
 
+	payload := vacancyPayload(h.Job)
+	payload["reason"] = reason
+	recordEvent(EventFire, []*Human{h}, payload)
+
 	// Вернуть вакантную позицию
 	h.Job.Parent.Mu.Lock()
 	h.Job.Parent.VacantPlaces[h.Job]++
@@ -329,8 +374,7 @@ func (h *Human) FireEmployee(reason string) {
 	h.JobTime = 721 // Установить в состояние безработного
 
 	// Добавить всплеск о потере работы
-	splash := NewSplash("job_loss", []string{"money", "stress", "career"}, 72)
-	h.Splashes = append(h.Splashes, splash)
+	h.EmitSplash(SplashJobLoss)
 }
 
 // CanBeFired определяет, может ли человек быть уволен на основе различных факторов
@@ -343,64 +387,100 @@ func (h *Human) CanBeFired() (bool, string) {
 	var fireProb float64 = 0.0
 	var reason string
 
+	// На больничном не увольняют
+	if h.IsSick() {
+		return false, ""
+	}
+
 	// 1. Плохая производительность (новые сотрудники с малым опытом)
-	if h.JobTime < 168 { // Менее 168 часов (1 неделя) опыта
-		fireProb += 0.01 // 1% шанс
+	if h.JobTime < config.NewEmployeePeriod { // Менее 168 часов (1 неделя) опыта
+		fireProb += config.PoorPerformanceFireRate // 1% шанс
 		reason = "poor_performance"
 	}
 
 	// 2. Экономический спад (умеренный шанс)
-	if utils.GlobalRandom.NextFloat() < 0.0005 { // 0.05% шанс в час
-		fireProb += 0.03 // 3% дополнительный шанс
+	if h.Rand.NextFloat() < config.EconomicDownturnRate { // 0.05% шанс в час
+		fireProb += config.EconomicDownturnFireRate // 3% дополнительный шанс
 		reason = "economic_downturn"
 	}
 
 	// 3. Реструктуризация компании (для высокооплачиваемых сотрудников)
-	if h.Job.Payment > 60000 { // Высокооплачиваемые сотрудники
-		fireProb += 0.0003 // 0.03% шанс
+	if h.Job.Payment > config.HighSalaryThreshold { // Высокооплачиваемые сотрудники
+		fireProb += config.RestructuringFireRate // 0.03% шанс
 		reason = "restructuring"
 	}
 
 	// 4. Поведенческие проблемы (умеренное влияние)
 	negativeSpashes := 0
 	for _, splash := range h.Splashes {
-		if splash.Name == "stress" || splash.Name == "job_loss" {
+		if splash.Name == "stress" || splash.Name == SplashJobLoss {
 			negativeSpashes++
 		}
 	}
 	if negativeSpashes > 1 { // Если есть негативные всплески
-		fireProb += 0.005 // 0.5% дополнительный шанс
+		fireProb += config.BehavioralIssuesFireRate // 0.5% дополнительный шанс
 		reason = "behavioral_issues"
 	}
 
 	// 5. Возрастная дискриминация (небольшой шанс для пожилых работников)
-	if h.Age > 55 { // Возрастной порог
-		fireProb += 0.0001 // 0.01% шанс
+	if h.Age > config.AgeDiscriminationThreshold { // Возрастной порог
+		fireProb += config.AgeDiscriminationFireRate // 0.01% шанс
 		reason = "age_discrimination"
 	}
 
 	// 6. Случайные увольнения для поддержания уровня безработицы
-	if utils.GlobalRandom.NextFloat() < 0.00001 { // Очень маленький базовый шанс
-		fireProb += 0.001 // 0.1% шанс
+	if h.Rand.NextFloat() < config.RandomLayoffBaseRate { // Очень маленький базовый шанс
+		fireProb += config.RandomLayoffFireRate // 0.1% шанс
 		reason = "random_layoff"
 	}
 
-	return utils.GlobalRandom.NextFloat() < fireProb, reason
+	// 7. Частые больничные
+	if h.SickLeaveHours > config.MaxSickLeaveHours {
+		fireProb += config.SickLeaveFireRate // 0.2% шанс
+		reason = "frequent_sick_leave"
+	}
+
+	return h.Rand.NextFloat() < fireProb, reason
 }
 
 // handleMovement управляет перемещением между зданиями в зависимости от времени суток
 func (h *Human) handleMovement() {
 	currentHour := utils.GetHourOfDay(utils.GlobalTick.Get())
 
+	// Посещение здания (эффект !goto) заканчивается при следующем перемещении
+	if h.isVisiting() {
+		h.deferShared(h.leaveActivityBuilding)
+	}
+
+	// Больные лежат в больнице или дома, не ходят на работу и в школу
+	if h.InBed() {
+		if h.AtSchool {
+			h.deferShared(h.leaveSchool)
+		}
+		if h.Hospital != nil {
+			h.CurrentBuilding = h.Hospital
+		} else if h.ResidentialBuilding != nil {
+			h.CurrentBuilding = h.ResidentialBuilding
+		}
+		return
+	}
+
+	// Дети школьного возраста на время уроков уходят в школу
+	if h.isSchoolAge() && isSchoolHour() {
+		h.deferShared(h.attendSchool)
+	} else if h.AtSchool {
+		h.deferShared(h.leaveSchool)
+	}
+
 	// Идти на работу в рабочие часы (9:00-17:59) если трудоустроен и это рабочий день
-	if currentHour >= 9 && currentHour < 18 && h.Job != nil && h.WorkBuilding != nil && utils.IsWorkDay(utils.GlobalTick.Get()) {
+	if h.isWorkingHour() {
 		if h.CurrentBuilding != h.WorkBuilding {
 			h.CurrentBuilding = h.WorkBuilding
 		}
 	}
 
 	// Идти домой после работы (18:00+) или в нерабочие часы
-	if (currentHour >= 18 || currentHour < 9 || !utils.IsWorkDay(utils.GlobalTick.Get())) && h.ResidentialBuilding != nil {
+	if (!utils.IsWorkTime(currentHour) || !utils.IsWorkDay(utils.GlobalTick.Get())) && h.ResidentialBuilding != nil {
 		if h.CurrentBuilding != h.ResidentialBuilding {
 			h.CurrentBuilding = h.ResidentialBuilding
 		}
@@ -440,7 +520,11 @@ func (h *Human) MarryWith(other *Human) {
 	other.MaritalStatus = Married
 	other.Spouse = h
 
-	// Добавить к семейным отношениям если еще не там
+	// Отношения в новом браке начинаются с нуля
+	h.SpouseBond = 0
+	other.SpouseBond = 0
+
+	// Добавить к семейным отношениям если еще не там (значение - длительность брака в годах)
 	if _, exists := h.Family[other]; !exists {
 		h.Family[other] = 0.0
 	}
@@ -448,13 +532,32 @@ func (h *Human) MarryWith(other *Human) {
 		other.Family[h] = 0.0
 	}
 
+	recordEvent(EventMarriage, []*Human{h, other}, nil)
+
 	// Невеста переезжает в жилое здание жениха (всегда, даже если в том же здании)
+	// и вместе с несовершеннолетними детьми переходит в его домохозяйство
+	if bride.ResidentialBuilding != nil && groom.ResidentialBuilding == nil {
+		// Жених без жилья (например, после развода) переезжает к невесте
+		bride.ResidentialBuilding.JoinFamily(groom)
+		if bride.Household != nil {
+			groom.moveToHousehold(bride.Household)
+		}
+		return
+	}
 	if bride.ResidentialBuilding != nil && groom.ResidentialBuilding != nil {
 		bride.ResidentialBuilding.MoveToSpouse(bride, groom)
 	}
+	if groom.Household != nil {
+		bride.moveToHousehold(groom.Household)
+		groom.Household.Residence = groom.ResidentialBuilding
+	}
 }
 
-// Divorce завершает брак между двумя людьми
+// Divorce завершает брак между двумя людьми.
+// Деньги супругов делятся поровну. В общем жилье остается владелец квартиры, а если квартира ничья -
+// супруг с большей зарплатой (см. divorceStayer). Несовершеннолетние дети остающегося живут с ним
+// в общем жилье и домохозяйстве. Уходящий забирает половину бюджета домохозяйства и своих детей
+// от других браков, съезжает и покупает квартиру у администрации (если хватает денег)
 func (h *Human) Divorce() {
 	if h.MaritalStatus != Married || h.Spouse == nil {
 		return // Не женат/замужем
@@ -463,16 +566,140 @@ func (h *Human) Divorce() {
 	spouse := h.Spouse
 
 	// Завершить двусторонний брак
-	h.MaritalStatus = Single
+	h.MaritalStatus = Divorced
 	h.Spouse = nil
-	spouse.MaritalStatus = Single
+	spouse.MaritalStatus = Divorced
 	spouse.Spouse = nil
+	h.DivorceCount++
+	spouse.DivorceCount++
+	recordEvent(EventDivorce, []*Human{h, spouse}, nil)
+
+	// Удалить семейные связи бывших супругов
+	delete(h.Family, spouse)
+	delete(spouse.Family, h)
+	h.SpouseBond = 0
+	spouse.SpouseBond = 0
+
+	// Разделить деньги поровну
+	total := h.Money + spouse.Money
+	h.Money = total / 2
+	spouse.Money = total - h.Money
+
+	stayer, leaver := divorceStayer(h, spouse)
+
+	// Дети остающегося живут с ним
+	if stayer.ResidentialBuilding != nil {
+		for _, child := range sortedHumans(stayer.Children) {
+			if child.Dead || child.Age >= config.AdultAge || child.ResidentialBuilding == stayer.ResidentialBuilding {
+				continue
+			}
+			if child.ResidentialBuilding != nil {
+				child.ResidentialBuilding.Vacate(child)
+			}
+			stayer.ResidentialBuilding.JoinFamily(child)
+		}
+	}
+
+	// Уходящий покидает общее домохозяйство с половиной бюджета и своими детьми от других браков
+	var ownChildren []*Human
+	for _, child := range sortedHumans(leaver.Children) {
+		if _, common := child.Parents[stayer]; !common && !child.Dead && child.Age < config.AdultAge {
+			ownChildren = append(ownChildren, child)
+		}
+	}
+	if leaver.Household != nil && leaver.Household == stayer.Household {
+		old := leaver.Household
+		household := leaver.splitHousehold(0.5)
+		for _, child := range ownChildren {
+			if child.Household == old {
+				child.joinHousehold(household)
+			}
+		}
+	}
+
+	// Уходящий съезжает из общего жилья и ищет новое
+	if home := leaver.ResidentialBuilding; home != nil && home == stayer.ResidentialBuilding {
+		home.Vacate(leaver)
+		leaver.CurrentBuilding = nil
+		leaver.findHousing()
+		if leaver.ResidentialBuilding != nil {
+			for _, child := range ownChildren {
+				if child.ResidentialBuilding == home {
+					home.Vacate(child)
+					leaver.ResidentialBuilding.JoinFamily(child)
+				}
+			}
+		}
+	}
+
+	// Развод - сильный стресс для обоих
+	for _, person := range []*Human{h, spouse} {
+		person.EmitSplash(SplashDivorce)
+	}
+}
+
+// divorceStayer решает, кто из разводящихся супругов остается в общем жилье, а кто уходит.
+// Остается владелец общей квартиры; если ей никто не владеет - супруг с жильем, затем с большей зарплатой,
+// затем с большими деньгами (при полном равенстве - тот, кто раньше появился в симуляции)
+func divorceStayer(a, b *Human) (stayer, leaver *Human) {
+	if home := a.ResidentialBuilding; home != nil && home == b.ResidentialBuilding {
+		if home.IsOwner(a) {
+			return a, b
+		}
+		if home.IsOwner(b) {
+			return b, a
+		}
+	}
+	if (a.ResidentialBuilding != nil) != (b.ResidentialBuilding != nil) {
+		if a.ResidentialBuilding != nil {
+			return a, b
+		}
+		return b, a
+	}
+	if a.salary() != b.salary() {
+		if a.salary() > b.salary() {
+			return a, b
+		}
+		return b, a
+	}
+	if a.Money != b.Money {
+		if a.Money > b.Money {
+			return a, b
+		}
+		return b, a
+	}
+	if GlobalHumanStorage.Get(a) < GlobalHumanStorage.Get(b) {
+		return a, b
+	}
+	return b, a
+}
+
+// salary возвращает месячную зарплату человека (0 без работы)
+func (h *Human) salary() int {
+	if h.Job == nil {
+		return 0
+	}
+	return h.Job.Payment
+}
+
+// findHousing покупает квартиру у администрации в домашнем городе, если хватает денег
+func (h *Human) findHousing() {
+	for _, building := range GetResidentialBuildings(h.HomeLocation) {
+		if building.BuyApartmentFromAdmin(h) {
+			return
+		}
+	}
 }
 
 // IsCompatibleWith проверяет, совместимы ли два человека для брака
 func (h *Human) IsCompatibleWith(other *Human) bool {
-	// Проверить, что оба одинокие
-	if h.MaritalStatus != Single || other.MaritalStatus != Single {
+	// Дети не вступают в брак
+	if h.Age < config.AdultAge || other.Age < config.AdultAge {
+		return false
+	}
+
+	// Проверить, что оба не состоят в браке
+	if h.MaritalStatus == Married || other.MaritalStatus == Married {
 		return false
 	}
 
@@ -525,16 +752,15 @@ func (h *Human) CanHaveChildren() bool {
 	}
 }
 
-// GetFamilyIncome вычисляет совокупный доход супружеской пары
+// GetFamilyIncome вычисляет совокупный месячный доход домохозяйства человека
 func (h *Human) GetFamilyIncome() int64 {
-	income := int64(0)
-	if h.Job != nil {
-		income += int64(h.Job.Payment)
-	}
-	if h.Spouse != nil && h.Spouse.Job != nil {
-		income += int64(h.Spouse.Job.Payment)
+	if h.Household == nil {
+		if h.Job != nil {
+			return int64(h.Job.Payment)
+		}
+		return 0
 	}
-	return income
+	return h.Household.MonthlyIncome()
 }
 
 // ShouldPlanChild определяет, должна ли пара планировать ребенка
@@ -596,13 +822,12 @@ func (h *Human) PlanChild() {
 		baseProb := config.BaseBirthPlanningProbability / (30 * 24) // Per hour probability
 		adjustedProb := baseProb * cityCoefficient
 
-		if utils.GlobalRandom.NextFloat() < adjustedProb {
+		if h.Rand.NextFloat() < adjustedProb {
 			h.IsPregnant = true
 			h.PregnancyTime = 0
 
 			// Добавить всплеск беременности
-			splash := NewSplash("pregnancy", []string{"family", "health", "responsibility"}, config.PregnancyDurationHours)
-			h.Splashes = append(h.Splashes, splash)
+			h.EmitSplash(SplashPregnancy)
 		}
 	}
 }
@@ -630,154 +855,99 @@ func (h *Human) GiveBirth(people []*Human, globalTargets []*GlobalTarget) *Human
 	h.IsPregnant = false
 	h.PregnancyTime = 0
 
-	// Создать ребенка с родителями
+	// Создать ребенка с родителями (если брак распался во время беременности, отца в семье нет)
 	parents := make(map[*Human]bool)
 	parents[h] = true
-	parents[h.Spouse] = true
+	if h.Spouse != nil {
+		parents[h.Spouse] = true
+	}
 
 	child := NewHuman(parents, h.HomeLocation, globalTargets)
 	child.Age = 0.0 // Новорожденный
 	child.Money = 0 // Дети не имеют денег
-	child.ResidentialBuilding = h.ResidentialBuilding
-	child.CurrentBuilding = h.ResidentialBuilding
-
-	// Добавить ребенка к детям родителей
-	h.Children[child] = 0.0
-	h.Spouse.Children[child] = 0.0
-
-	// Добавить родителей к родителям ребенка
-	child.Parents[h] = 0.0
-	child.Parents[h.Spouse] = 0.0
-
-	// Добавить всплеск рождения родителям
-	birthSplash := NewSplash("child_birth", []string{"family", "happiness", "responsibility"}, 168) // 1 неделя
-	h.Splashes = append(h.Splashes, birthSplash)
-	h.Spouse.Splashes = append(h.Spouse.Splashes, birthSplash)
-
-	return child
-}
-
-// redistributeWealth распределяет деньги семье при смерти
-func (h *Human) redistributeWealth() {
-	if h.Money <= 0 {
-		return
-	}
+	child.Health = healthBaseline(child.Age)
 
-	var candidates []*Human
-	for family := range h.Family {
-		if !family.Dead {
-			candidates = append(candidates, family)
-		}
+	// Цели, назначенные по случайному возрасту, заменить детскими
+	child.GlobalTargets = make(map[*GlobalTarget]bool)
+	child.assignGlobalTargets(globalTargets)
+	if h.ResidentialBuilding != nil {
+		h.ResidentialBuilding.JoinFamily(child)
 	}
-	for child := range h.Children {
-		if !child.Dead {
-			candidates = append(candidates, child)
-		}
-	}
-	for parent := range h.Parents {
-		if !parent.Dead {
-			candidates = append(candidates, parent)
-		}
+	if h.Household != nil {
+		child.joinHousehold(h.Household)
 	}
 
-	if len(candidates) > 0 {
-		share := h.Money / int64(len(candidates))
-		for _, candidate := range candidates {
-			candidate.Money += share
-		}
-	}
-}
+	// Роды проходят в больнице, если в ней есть место; иначе дома
+	hospital := h.admitToHospital(AdmissionChildbirth, config.MaternityStayHours)
 
-// redistributeMoneyInFamily пытается получить деньги от членов семьи
-func (h *Human) redistributeMoneyInFamily() {
-	for family := range h.Family {
-		if h.Money < 0 && family.Money > 0 {
-			transfer := int64(math.Min(float64(-h.Money), float64(family.Money)))
-			h.Money += transfer
-			family.Money -= transfer
-		}
+	agents := []*Human{child, h}
+	if h.Spouse != nil {
+		agents = append(agents, h.Spouse)
 	}
+	for _, parent := range agents[1:] {
+		// Связать родителя и ребенка
+		parent.Children[child] = 0.0
+		child.Parents[parent] = 0.0
 
-	for child := range h.Children {
-		if h.Money < 0 && child.Money > 0 {
-			transfer := int64(math.Min(float64(-h.Money), float64(child.Money)))
-			h.Money += transfer
-			child.Money -= transfer
-		}
+		// Добавить всплеск рождения родителю
+		parent.EmitSplash(SplashChildBirth)
 	}
+	recordEvent(EventBirth, agents, map[string]interface{}{
+		"city":     h.HomeLocation.Name,
+		"hospital": hospital != nil,
+	})
 
-	for parent := range h.Parents {
-		if h.Money < 0 && parent.Money > 0 {
-			transfer := int64(math.Min(float64(-h.Money), float64(parent.Money)))
-			h.Money += transfer
-			parent.Money -= transfer
-		}
-	}
+	return child
 }
 
-// performActions обрабатывает основную логику принятия решений
+// performActions выполняет план, выбранный планировщиком, и учитывает продвижение по целям.
+// Цена действия списывается сразу; действие, занимающее время, дает результат только по завершении
 func (h *Human) performActions() {
-	if len(h.GlobalTargets) == 0 {
+	plan := CurrentPlanner.Plan(h)
+	if plan == nil {
 		return
 	}
 
-	rating := make(map[float64][]*GlobalTarget)
-
-	if len(h.Splashes) > 0 {
-		// Оценить цели на основе всплесков
-		for target := range h.GlobalTargets {
-			var counter uint64 = 0
-			for _, splash := range h.Splashes {
-				if len(utils.IntersectSlices(getKeysFromMap(target.Tags), getKeysFromMap(splash.Tags))) > 0 {
-					counter++
-				}
-			}
-			rate := (target.Power * float64(counter)) / float64(len(h.Splashes))
-			rating[rate] = append(rating[rate], target)
-		}
-	} else {
-		// Оценить цели на основе выполнимости и силы
-		for target := range h.GlobalTargets {
-			var executable float64 = 0
-			if target.Executable(h) {
-				executable = 1
-			}
-			rate := executable * target.Power
-			rating[rate] = append(rating[rate], target)
+	if plan.Action != nil {
+		h.Money -= plan.Action.Price
+		if plan.Action.TimeToExecute > 0 {
+			h.startActivity(plan.Action, plan.Global, plan.Local)
+			return
 		}
+		plan.Action.Apply(h)
 	}
+	h.progressTargets(plan.Global, plan.Local, plan.Action)
+}
 
-	if len(rating) == 0 {
-		return
-	}
-
-	// Получить цели с наивысшим рейтингом
-	var maxRate float64 = -1
-	for rate := range rating {
-		if rate > maxRate {
-			maxRate = rate
+// progressTargets засчитывает выполненное действие локальной и глобальной цели
+// и отмечает выполненные цели. selectedGlobalTarget может быть nil, если цель уже снята
+func (h *Human) progressTargets(selectedGlobalTarget *GlobalTarget, selectedLocalTarget *LocalTarget, action *Action) {
+	if action != nil {
+		selectedLocalTarget.MarkAsExecuted(h, action)
+		if selectedGlobalTarget != nil {
+			selectedGlobalTarget.LastProgress = utils.GlobalTick.Get()
 		}
 	}
 
-	candidates := rating[maxRate]
-	selectedGlobalTarget := candidates[utils.GlobalRandom.NextInt(len(candidates))]
-
-	selectedLocalTarget := selectedGlobalTarget.ChooseTarget(h)
-	if selectedLocalTarget != nil {
-		selectedAction := selectedLocalTarget.ChooseAction(h)
-		if selectedAction != nil {
-			selectedAction.Apply(h)
-			selectedLocalTarget.MarkAsExecuted(selectedAction)
-
-			if selectedLocalTarget.IsExecutedFull() {
-				selectedGlobalTarget.MarkAsExecuted(selectedLocalTarget)
-
-				if selectedGlobalTarget.IsExecutedFull() {
-					h.CompletedGlobalTargets[selectedGlobalTarget] = true
-					delete(h.GlobalTargets, selectedGlobalTarget)
-				}
-			}
-		}
+	// Локальная цель могла быть уже выполнена ранее в рамках другой глобальной цели
+	if selectedGlobalTarget == nil || !selectedLocalTarget.IsExecutedFull(h) {
+		return
+	}
+	selectedGlobalTarget.MarkAsExecuted(selectedLocalTarget)
+	h.deferEvent(EventTargetCompleted, []*Human{h}, map[string]interface{}{
+		"target": selectedLocalTarget.Name,
+		"kind":   "local",
+		"global": selectedGlobalTarget.Name,
+	})
+
+	if selectedGlobalTarget.IsExecutedFull() {
+		selectedGlobalTarget.LastProgress = utils.GlobalTick.Get()
+		h.CompletedGlobalTargets[selectedGlobalTarget] = true
+		delete(h.GlobalTargets, selectedGlobalTarget)
+		h.deferEvent(EventTargetCompleted, []*Human{h}, map[string]interface{}{
+			"target": selectedGlobalTarget.Name,
+			"kind":   "global",
+		})
 	}
 }
 
@@ -31,3 +31,22 @@ func (hs *HumanStorage) Get(human interface{}) int {
 	}
 	return -1
 }
+
+// Count возвращает количество выданных ID
+func (hs *HumanStorage) Count() int {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+	return hs.count
+}
+
+// Restore очищает хранилище и заново связывает людей с их сохраненными ID.
+// count - значение счетчика, с которого продолжится выдача новых ID
+func (hs *HumanStorage) Restore(ids map[interface{}]int, count int) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	hs.links = make(map[interface{}]int, len(ids))
+	for human, id := range ids {
+		hs.links[human] = id
+	}
+	hs.count = count
+}
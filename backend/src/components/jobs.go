@@ -1,6 +1,6 @@
 package components
 
-import "github.com/fallra1n/humanity/src/utils"
+import "github.com/fallra1n/humanity/src/config"
 
 // findJob пытается найти новую работу
 func findJob(h *Human) {
@@ -31,7 +31,7 @@ func findJob(h *Human) {
 						// 2. Имеет как минимум 80% требуемых навыков
 						// 3. Безработный и очень отчаянный (деньги < -10000)
 						if requiredSkills == 0 ||
-							float64(hasSkills)/float64(requiredSkills) >= 0.8 ||
+							float64(hasSkills)/float64(requiredSkills) >= config.MinSkillMatchForJob ||
 							(h.Job == nil && h.Money < -10000) {
 
 							// Если трудоустроен, рассматривать только более высокооплачиваемые работы
@@ -49,10 +49,15 @@ func findJob(h *Human) {
 	h.HomeLocation.Mu.RUnlock()
 
 	if len(possibleJobs) > 0 {
-		chosen := possibleJobs[utils.GlobalRandom.NextInt(len(possibleJobs))]
+		sortVacancies(possibleJobs)
+		chosen := possibleJobs[h.Rand.NextInt(len(possibleJobs))]
 
 		// Уволиться со старой работы
 		if h.Job != nil {
+			quit := vacancyPayload(h.Job)
+			quit["reason"] = "job_switch"
+			recordEvent(EventQuit, []*Human{h}, quit)
+
 			h.Job.Parent.Mu.Lock()
 			h.Job.Parent.VacantPlaces[h.Job]++
 			h.Job.Parent.Mu.Unlock()
@@ -63,8 +68,57 @@ func findJob(h *Human) {
 		h.Job = chosen
 		chosen.Parent.VacantPlaces[chosen]--
 		h.JobTime = 0
+		h.SickLeaveHours = 0
 		// Установить рабочее здание в здание, где находится работа
 		h.WorkBuilding = chosen.Parent.Building
 		chosen.Parent.Mu.Unlock()
+		recordEvent(EventHire, []*Human{h}, vacancyPayload(chosen))
+	}
+}
+
+// qualifiesFor проверяет, что у человека есть нужная доля требуемых навыков
+func (h *Human) qualifiesFor(vacancy *Vacancy, minMatch float64) bool {
+	requiredSkills := 0
+	hasSkills := 0
+
+	for tag := range vacancy.RequiredTags {
+		requiredSkills++
+		if h.Items[tag] > 0 {
+			hasSkills++
+		}
+	}
+
+	return requiredSkills == 0 || float64(hasSkills)/float64(requiredSkills) >= minMatch
+}
+
+// takeJob занимает позицию вакансии
+func (h *Human) takeJob(vacancy *Vacancy) {
+	vacancy.Parent.Mu.Lock()
+	h.Job = vacancy
+	vacancy.Parent.VacantPlaces[vacancy]--
+	h.JobTime = 0
+	h.SickLeaveHours = 0
+	h.WorkBuilding = vacancy.Parent.Building
+	vacancy.Parent.Mu.Unlock()
+
+	recordEvent(EventHire, []*Human{h}, vacancyPayload(vacancy))
+}
+
+// leaveJob освобождает позицию, которую занимает человек; reason попадает в событие увольнения
+func (h *Human) leaveJob(reason string) {
+	if h.Job == nil {
+		return
 	}
+
+	payload := vacancyPayload(h.Job)
+	payload["reason"] = reason
+	recordEvent(EventQuit, []*Human{h}, payload)
+
+	h.Job.Parent.Mu.Lock()
+	h.Job.Parent.VacantPlaces[h.Job]++
+	h.Job.Parent.Mu.Unlock()
+
+	h.Job = nil
+	h.JobTime = 721 // Установить в состояние безработного
+	h.WorkBuilding = nil
 }
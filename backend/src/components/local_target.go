@@ -1,17 +1,19 @@
 package components
 
-import (
-	"github.com/fallra1n/humanity/src/utils"
-	"sync"
-)
+import "github.com/fallra1n/humanity/src/utils"
 
-// LocalTarget представляет краткосрочную цель
+// LocalTarget представляет краткосрочную цель.
+// Определение цели общее для всех людей и не меняется во время симуляции;
+// продвижение каждого человека хранится в Human.LocalProgress
 type LocalTarget struct {
 	Name            string
 	Tags            map[string]bool
-	ActionsPossible map[*Action]bool
+	ActionsPossible map[*Action]bool // Действия, закрывающие хотя бы один тег цели
+}
+
+// LocalTargetProgress - продвижение человека по локальной цели
+type LocalTargetProgress struct {
 	ActionsExecuted map[*Action]bool
-	Mu              sync.RWMutex
 }
 
 // NewLocalTarget создает новую локальную цель
@@ -32,29 +34,35 @@ func NewLocalTarget(name string, tags []string, allActions []*Action) *LocalTarg
 		Name:            name,
 		Tags:            tagSet,
 		ActionsPossible: actionsPossible,
-		ActionsExecuted: make(map[*Action]bool),
 	}
 }
 
-// MarkAsExecuted отмечает действие как выполненное
-func (lt *LocalTarget) MarkAsExecuted(action *Action) {
-	lt.Mu.Lock()
-	defer lt.Mu.Unlock()
-	delete(lt.ActionsPossible, action)
-	lt.ActionsExecuted[action] = true
+// executed возвращает действия цели, уже выполненные человеком
+func (lt *LocalTarget) executed(person *Human) map[*Action]bool {
+	if progress := person.LocalProgress[lt]; progress != nil {
+		return progress.ActionsExecuted
+	}
+	return nil
 }
 
-// IsExecutedFull проверяет, покрыты ли все теги выполненными действиями
-func (lt *LocalTarget) IsExecutedFull() bool {
-	lt.Mu.RLock()
-	defer lt.Mu.RUnlock()
+// MarkAsExecuted отмечает действие как выполненное человеком
+func (lt *LocalTarget) MarkAsExecuted(person *Human, action *Action) {
+	progress := person.LocalProgress[lt]
+	if progress == nil {
+		progress = &LocalTargetProgress{ActionsExecuted: make(map[*Action]bool)}
+		person.LocalProgress[lt] = progress
+	}
+	progress.ActionsExecuted[action] = true
+}
 
+// IsExecutedFull проверяет, покрыты ли все теги действиями, выполненными человеком
+func (lt *LocalTarget) IsExecutedFull(person *Human) bool {
 	remainingTags := make(map[string]bool)
 	for tag := range lt.Tags {
 		remainingTags[tag] = true
 	}
 
-	for action := range lt.ActionsExecuted {
+	for action := range lt.executed(person) {
 		for tag := range action.Tags {
 			delete(remainingTags, tag)
 		}
@@ -63,10 +71,9 @@ func (lt *LocalTarget) IsExecutedFull() bool {
 	return len(remainingTags) == 0
 }
 
-// Executable проверяет, может ли локальная цель быть выполнена
+// Executable проверяет, может ли локальная цель быть выполнена человеком
 func (lt *LocalTarget) Executable(person *Human) bool {
-	lt.Mu.RLock()
-	defer lt.Mu.RUnlock()
+	executed := lt.executed(person)
 
 	unclosedTags := make(map[string]bool)
 	for tag := range lt.Tags {
@@ -74,7 +81,7 @@ func (lt *LocalTarget) Executable(person *Human) bool {
 	}
 
 	// Удалить выполненные теги
-	for action := range lt.ActionsExecuted {
+	for action := range executed {
 		for tag := range action.Tags {
 			delete(unclosedTags, tag)
 		}
@@ -82,7 +89,7 @@ func (lt *LocalTarget) Executable(person *Human) bool {
 
 	// Проверить, могут ли оставшиеся теги быть закрыты
 	for action := range lt.ActionsPossible {
-		if action.Executable(person) {
+		if !executed[action] && action.Executable(person) {
 			for tag := range action.Tags {
 				delete(unclosedTags, tag)
 			}
@@ -94,15 +101,14 @@ func (lt *LocalTarget) Executable(person *Human) bool {
 
 // ChooseAction выбирает лучшее действие для этой цели
 func (lt *LocalTarget) ChooseAction(person *Human) *Action {
-	lt.Mu.RLock()
-	defer lt.Mu.RUnlock()
+	executed := lt.executed(person)
 
 	leftTags := make(map[string]bool)
 	for tag := range lt.Tags {
 		leftTags[tag] = true
 	}
 
-	for action := range lt.ActionsExecuted {
+	for action := range executed {
 		for tag := range action.Tags {
 			delete(leftTags, tag)
 		}
@@ -110,7 +116,7 @@ func (lt *LocalTarget) ChooseAction(person *Human) *Action {
 
 	rating := make(map[uint64][]*Action)
 	for action := range lt.ActionsPossible {
-		if action.Executable(person) {
+		if !executed[action] && action.Executable(person) {
 			var rate uint64 = 0
 			for tag := range action.Tags {
 				if leftTags[tag] {
@@ -132,7 +138,8 @@ func (lt *LocalTarget) ChooseAction(person *Human) *Action {
 
 		candidates := rating[maxRate]
 		if len(candidates) > 0 {
-			return candidates[utils.GlobalRandom.NextInt(len(candidates))]
+			sortActions(candidates)
+			return candidates[person.Rand.NextInt(len(candidates))]
 		}
 	}
 
@@ -0,0 +1,54 @@
+package components
+
+import "testing"
+
+// Определение локальной цели общее для всех людей: продвижение одного человека
+// не должно влиять на выбор действий и проверки другого
+func TestLocalTargetProgressIsPerPerson(t *testing.T) {
+	both := NewAction("both", 0, 0, []string{"a", "b"}, nil, nil, nil, 0, nil, "")
+	third := NewAction("third", 0, 0, []string{"c"}, nil, nil, nil, 0, nil, "")
+	target := NewLocalTarget("target", []string{"a", "b", "c"}, []*Action{both, third})
+
+	first := NewHuman(map[*Human]bool{}, nil, nil)
+	second := NewHuman(map[*Human]bool{}, nil, nil)
+
+	check := func(person *Human, name string, wantAction *Action, wantExecutable, wantFull bool) {
+		t.Helper()
+		if got := target.ChooseAction(person); got != wantAction {
+			t.Errorf("%s: ChooseAction = %v, want %v", name, actionName(got), actionName(wantAction))
+		}
+		if got := target.Executable(person); got != wantExecutable {
+			t.Errorf("%s: Executable = %v, want %v", name, got, wantExecutable)
+		}
+		if got := target.IsExecutedFull(person); got != wantFull {
+			t.Errorf("%s: IsExecutedFull = %v, want %v", name, got, wantFull)
+		}
+	}
+
+	check(second, "second before", both, true, false)
+
+	// Первый выполняет действие, закрывающее больше всего тегов, и переходит к оставшемуся
+	target.MarkAsExecuted(first, both)
+	check(first, "first", third, true, false)
+	check(second, "second after first", both, true, false)
+	if second.LocalProgress[target] != nil {
+		t.Fatalf("second has progress recorded by first")
+	}
+
+	// Второй выполняет цель целиком, первый ее завершает
+	target.MarkAsExecuted(second, both)
+	target.MarkAsExecuted(second, third)
+	check(second, "second done", nil, true, true)
+	check(first, "first after second", third, true, false)
+
+	target.MarkAsExecuted(first, third)
+	check(first, "first done", nil, true, true)
+	check(second, "second after first done", nil, true, true)
+}
+
+func actionName(action *Action) string {
+	if action == nil {
+		return "<nil>"
+	}
+	return action.Name
+}
@@ -3,6 +3,8 @@ package components
 type MaritalStatus string
 
 const (
-	Single  MaritalStatus = "single"
-	Married MaritalStatus = "married"
+	Single   MaritalStatus = "single"
+	Married  MaritalStatus = "married"
+	Divorced MaritalStatus = "divorced"
+	Widowed  MaritalStatus = "widowed"
 )
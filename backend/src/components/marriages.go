@@ -3,19 +3,24 @@ package components
 import "github.com/fallra1n/humanity/src/utils"
 
 // ProcessMarriages обрабатывает формирование браков между совместимыми людьми
-func ProcessMarriages(people []*Human) {
-	// Группировать одиноких людей по их текущему зданию
+func ProcessMarriages(people []*Human, rng *utils.Random) {
+	// Группировать свободных (одиноких и разведенных) людей по их текущему зданию (в порядке первого появления здания)
 	buildingGroups := make(map[*Building][]*Human)
+	var buildingOrder []*Building
 
 	for _, person := range people {
-		if person.Dead || person.CurrentBuilding == nil || person.MaritalStatus != Single {
+		if person.Dead || person.CurrentBuilding == nil || person.MaritalStatus == Married {
 			continue
 		}
+		if _, exists := buildingGroups[person.CurrentBuilding]; !exists {
+			buildingOrder = append(buildingOrder, person.CurrentBuilding)
+		}
 		buildingGroups[person.CurrentBuilding] = append(buildingGroups[person.CurrentBuilding], person)
 	}
 
 	// Обработать потенциальные браки в каждом здании
-	for _, group := range buildingGroups {
+	for _, building := range buildingOrder {
+		group := buildingGroups[building]
 		if len(group) < 2 {
 			continue // Нужно как минимум 2 человека для формирования браков
 		}
@@ -29,7 +34,7 @@ func ProcessMarriages(people []*Human) {
 				// Проверить совместимость
 				if person1.IsCompatibleWith(person2) {
 					// Небольшой шанс пожениться (5% в час при совместимости)
-					if utils.GlobalRandom.NextFloat() < 0.05 {
+					if rng.NextFloat() < 0.05 {
 						person1.MarryWith(person2)
 					}
 				}
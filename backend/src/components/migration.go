@@ -0,0 +1,272 @@
+package components
+
+import (
+	"sort"
+
+	"github.com/fallra1n/humanity/src/config"
+	"github.com/fallra1n/humanity/src/utils"
+)
+
+// ProcessMigrations решает, кто из людей переезжает в другой город.
+// Переезд затрагивает жилье, вакансии и жителей двух городов, поэтому выполняется
+// последовательно после того, как все люди действовали
+func ProcessMigrations(people []*Human) {
+	if utils.GlobalTick.Get()%config.MigrationCheckInterval != 0 {
+		return
+	}
+
+	for _, person := range people {
+		if person.Dead || person.Age < config.AdultAge || person.BusyHours > 0 || person.InBed() {
+			continue
+		}
+
+		if path, vacancy := person.chooseMigration(); path != nil {
+			person.Migrate(path, vacancy)
+		}
+	}
+}
+
+// chooseMigration выбирает дорогу для переезда и, если переезд ради работы, вакансию в новом городе.
+// Причины переезда: супруг живет в другом городе, долгая безработица, заметно лучшая зарплата
+func (h *Human) chooseMigration() (*Path, *Vacancy) {
+	paths := h.outgoingPaths()
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	// 1. Супруг живет в другом городе - переехать к нему
+	if h.Spouse != nil && !h.Spouse.Dead && h.Spouse.HomeLocation != h.HomeLocation {
+		for _, path := range paths {
+			if path.To == h.Spouse.HomeLocation && h.canAffordMigration(path) {
+				return path, nil
+			}
+		}
+		return nil, nil
+	}
+
+	// 2. Долгая безработица - согласиться на любую подходящую работу
+	// 3. Работа в другом городе платит заметно больше текущей
+	var minPayment int
+	switch {
+	case h.Job == nil && h.UnemployedTime >= config.LongTermUnemploymentHours:
+		minPayment = 0
+	case h.Job != nil:
+		minPayment = int(float64(h.Job.Payment) * config.MigrationSalaryGain)
+	default:
+		return nil, nil
+	}
+
+	var bestPath *Path
+	var bestVacancy *Vacancy
+	for _, path := range paths {
+		vacancy := h.bestVacancyIn(path.To)
+		if vacancy == nil || vacancy.Payment < minPayment {
+			continue
+		}
+		if bestVacancy != nil && vacancy.Payment <= bestVacancy.Payment {
+			continue
+		}
+		if h.canAffordMigration(path) {
+			bestPath, bestVacancy = path, vacancy
+		}
+	}
+
+	if bestVacancy == nil || h.Rand.NextFloat() >= config.MigrationProbability {
+		return nil, nil
+	}
+	return bestPath, bestVacancy
+}
+
+// Migrate переезжает по дороге path вместе с семьей (супруг из того же города и несовершеннолетние дети).
+// Переезжающий платит за дорогу всех и покупает квартиру в городе назначения (или поселяется у супруга);
+// переезжающие владельцы продают свои квартиры, остальные просто выписываются. Все переезжающие увольняются и проводят в пути path.Time часов.
+// Если в домохозяйстве остается кто-то из непереезжающих, переезжающие заводят новое.
+// Если задана вакансия, переезжающий занимает ее
+func (h *Human) Migrate(path *Path, vacancy *Vacancy) {
+	movers := h.migratingFamily()
+	h.Money -= int64(path.Price) * int64(len(movers))
+	recordEvent(EventMigration, movers, map[string]interface{}{
+		"from":  path.From.Name,
+		"to":    path.To.Name,
+		"price": path.Price,
+		"time":  path.Time,
+	})
+
+	// Жилье в городе назначения: свое или супруга, иначе купить новое
+	home := h.residenceIn(path.To)
+	for _, mover := range movers {
+		old := mover.ResidentialBuilding
+		if old == nil || old == home {
+			continue
+		}
+		if old.IsOwner(mover) {
+			old.SellApartmentToAdmin(mover)
+		} else {
+			old.Vacate(mover)
+		}
+	}
+	if home == nil {
+		for _, building := range GetResidentialBuildings(path.To) {
+			if building.BuyApartmentFromAdmin(h) {
+				home = building
+				break
+			}
+		}
+	}
+
+	for _, mover := range movers {
+		// Начатые действия в старом городе обрываются
+		mover.stopActivity()
+		mover.leaveSchool()
+		mover.leaveHospital()
+
+		if home != nil && mover.ResidentialBuilding != home {
+			home.JoinFamily(mover)
+		}
+		mover.CurrentBuilding = mover.ResidentialBuilding
+
+		mover.leaveJob("migration")
+
+		mover.relocate(path.To)
+		mover.BusyHours += path.Time
+	}
+
+	h.moveHousehold(movers, home)
+
+	if vacancy != nil {
+		h.takeJob(vacancy)
+	}
+}
+
+// moveHousehold собирает переезжающих в одно домохозяйство с жильем home
+func (h *Human) moveHousehold(movers []*Human, home *Building) {
+	household := h.Household
+	moving := make(map[*Human]bool, len(movers))
+	for _, mover := range movers {
+		moving[mover] = true
+	}
+	if household != nil {
+		for member := range household.Members {
+			if !moving[member] {
+				household = nil
+				break
+			}
+		}
+	}
+	if household == nil {
+		household = NewHousehold(h)
+	}
+
+	for _, mover := range movers {
+		mover.joinHousehold(household)
+	}
+	household.Residence = home
+}
+
+// relocate переносит человека в список жителей другого города
+func (h *Human) relocate(city *Location) {
+	if old := h.HomeLocation; old != nil {
+		old.Mu.Lock()
+		delete(old.Humans, h)
+		old.Mu.Unlock()
+	}
+	city.Mu.Lock()
+	city.Humans[h] = true
+	city.Mu.Unlock()
+
+	h.HomeLocation = city
+}
+
+// outgoingPaths возвращает дороги из города человека, упорядоченные по городу назначения
+func (h *Human) outgoingPaths() []*Path {
+	h.HomeLocation.Mu.RLock()
+	defer h.HomeLocation.Mu.RUnlock()
+
+	var paths []*Path
+	for path := range h.HomeLocation.Paths {
+		if path.From == h.HomeLocation {
+			paths = append(paths, path)
+		}
+	}
+	sort.Slice(paths, func(i, j int) bool { return paths[i].To.Name < paths[j].To.Name })
+	return paths
+}
+
+// migratingFamily возвращает переезжающих: сам человек, супруг из того же города
+// и несовершеннолетние дети из того же города
+func (h *Human) migratingFamily() []*Human {
+	movers := []*Human{h}
+	if h.Spouse != nil && !h.Spouse.Dead && h.Spouse.HomeLocation == h.HomeLocation {
+		movers = append(movers, h.Spouse)
+	}
+	for _, child := range sortedHumans(h.Children) {
+		if !child.Dead && child.Age < config.AdultAge && child.HomeLocation == h.HomeLocation {
+			movers = append(movers, child)
+		}
+	}
+	return movers
+}
+
+// residenceIn возвращает жилье семьи в городе city: свое или супруга
+func (h *Human) residenceIn(city *Location) *Building {
+	if h.ResidentialBuilding != nil && h.ResidentialBuilding.Location == city {
+		return h.ResidentialBuilding
+	}
+	if h.Spouse != nil && !h.Spouse.Dead && h.Spouse.ResidentialBuilding != nil &&
+		h.Spouse.ResidentialBuilding.Location == city {
+		return h.Spouse.ResidentialBuilding
+	}
+	return nil
+}
+
+// canAffordMigration проверяет, хватит ли денег на дорогу для всей семьи
+// и (с учетом продажи своей квартиры, если человек ей владеет) на квартиру в городе назначения
+func (h *Human) canAffordMigration(path *Path) bool {
+	money := h.Money - int64(path.Price)*int64(len(h.migratingFamily()))
+	if money < 0 {
+		return false
+	}
+	if h.residenceIn(path.To) != nil {
+		return true
+	}
+
+	if h.ResidentialBuilding != nil && h.ResidentialBuilding.IsOwner(h) {
+		money += h.ResidentialBuilding.ApartmentPrice
+	}
+	for _, building := range GetResidentialBuildings(path.To) {
+		building.Mu.RLock()
+		available := building.Occupied < building.Capacity
+		building.Mu.RUnlock()
+		if available {
+			return money >= building.ApartmentPrice
+		}
+	}
+	return false
+}
+
+// bestVacancyIn возвращает самую высокооплачиваемую свободную вакансию города, подходящую человеку
+func (h *Human) bestVacancyIn(city *Location) *Vacancy {
+	var candidates []*Vacancy
+	for _, building := range GetWorkplaceBuildings(city) {
+		building.Mu.RLock()
+		for job := range building.Jobs {
+			job.Mu.RLock()
+			for vacancy, count := range job.VacantPlaces {
+				if count > 0 && h.qualifiesFor(vacancy, config.MinSkillMatchForJob) {
+					candidates = append(candidates, vacancy)
+				}
+			}
+			job.Mu.RUnlock()
+		}
+		building.Mu.RUnlock()
+	}
+
+	sortVacancies(candidates)
+	var best *Vacancy
+	for _, vacancy := range candidates {
+		if best == nil || vacancy.Payment > best.Payment {
+			best = vacancy
+		}
+	}
+	return best
+}
@@ -0,0 +1,206 @@
+package components
+
+import (
+	"math"
+
+	"github.com/fallra1n/humanity/src/config"
+)
+
+// Смертность.
+// Каждый час человек может умереть с вероятностью, которую дает годовой риск по модели
+// Гомперца-Мейкхема (таблица пола, см. config.MortalityTable). Риск складывается из трех причин:
+// несчастные случаи не зависят от возраста, риск болезней и старости растет экспоненциально.
+// Модификаторы из сценария (mortality.modifiers) умножают риск: выполненные цели здоровья его снижают,
+// стресс - повышает. Причина смерти записывается в событие death.
+// Умерший освобождает работу, место в школе или больнице и уходит из домохозяйства (бюджет опустевшего
+// домохозяйства входит в наследство), его имущество делят наследники (см. settleEstate),
+// супруг становится вдовцом, а друзья его теряют.
+// В конце часа симуляция переносит умерших в архив (см. Simulation.archiveDead)
+
+// DeathCause - причина смерти
+type DeathCause string
+
+const (
+	DeathAccident DeathCause = "accident"
+	DeathIllness  DeathCause = "illness"
+	DeathOldAge   DeathCause = "old_age"
+)
+
+// DeathCauses - все причины смерти в порядке розыгрыша
+var DeathCauses = []DeathCause{DeathAccident, DeathIllness, DeathOldAge}
+
+// deathHazards возвращает годовой риск смерти от каждой причины (в порядке DeathCauses)
+func (h *Human) deathHazards() []float64 {
+	table := h.Gender.MortalityTable()
+	aging := math.Exp(table.Growth * h.Age)
+
+	return []float64{
+		table.Accident * h.mortalityFactor(DeathAccident),
+		table.Illness * aging * h.mortalityFactor(DeathIllness) * h.illnessMortalityFactor(),
+		table.OldAge * aging * h.mortalityFactor(DeathOldAge),
+	}
+}
+
+// mortalityFactor перемножает модификаторы риска, действующие на причину смерти.
+// Модификатор без причины действует на болезни и старость
+func (h *Human) mortalityFactor(cause DeathCause) float64 {
+	factor := 1.0
+	for _, modifier := range config.MortalityModifiers {
+		if modifier.Cause != "" && DeathCause(modifier.Cause) != cause {
+			continue
+		}
+		if modifier.Cause == "" && cause == DeathAccident {
+			continue
+		}
+
+		switch {
+		case modifier.Target != "":
+			factor *= 1 + (modifier.Factor-1)*h.targetProgress(modifier.Target)
+		case modifier.Item != "":
+			if h.Items[modifier.Item] > 0 {
+				factor *= modifier.Factor
+			}
+		case modifier.SplashTag != "":
+			factor *= math.Pow(modifier.Factor, h.splashStrength(map[string]bool{modifier.SplashTag: true}))
+		}
+	}
+	return factor
+}
+
+// targetProgress возвращает прогресс глобальной цели человека: 1 - выполнена, 0 - ее нет
+func (h *Human) targetProgress(name string) float64 {
+	for target := range h.CompletedGlobalTargets {
+		if target.Name == name {
+			return 1
+		}
+	}
+	for target := range h.GlobalTargets {
+		if target.Name == name {
+			return target.Progress()
+		}
+	}
+	return 0
+}
+
+// rollDeath разыгрывает смерть за прошедший час.
+// Возвращает причину смерти или пустую строку, если человек остался жив
+func (h *Human) rollDeath() DeathCause {
+	if i := h.rollHazards(h.deathHazards()); i >= 0 {
+		return DeathCauses[i]
+	}
+	return ""
+}
+
+// rollHazards разыгрывает за прошедший час события с годовыми частотами hazards.
+// Возвращает номер случившегося события или -1, если не случилось ни одного
+func (h *Human) rollHazards(hazards []float64) int {
+	total := 0.0
+	for _, hazard := range hazards {
+		total += hazard
+	}
+
+	probability := 1 - math.Exp(-total/config.HoursPerYear)
+	roll := h.Rand.NextFloat()
+	if roll >= probability {
+		return -1
+	}
+
+	// Тот же бросок выбирает событие пропорционально его частоте
+	threshold := roll / probability * total
+	for i, hazard := range hazards {
+		threshold -= hazard
+		if threshold < 0 {
+			return i
+		}
+	}
+	return len(hazards) - 1
+}
+
+// die освобождает все, что занимал умерший, и обрывает его связи с живыми.
+// Вызывается только в последовательной фазе, в тот же час, когда человек умер
+func (h *Human) die() {
+	recordEvent(EventDeath, []*Human{h}, map[string]interface{}{"age": h.Age, "cause": string(h.DeathCause)})
+
+	h.stopActivity()
+	h.leaveSchool()
+	h.leaveHospital()
+	h.leaveJob("death")
+
+	h.leaveHousehold()
+	h.settleEstate()
+	h.widowSpouse()
+
+	// Друзья теряют умершего
+	for friend := range h.Friends {
+		delete(friend.Friends, h)
+	}
+	h.Friends = make(map[*Human]float64)
+
+	if h.HomeLocation != nil {
+		h.HomeLocation.Mu.Lock()
+		delete(h.HomeLocation.Humans, h)
+		h.HomeLocation.Mu.Unlock()
+	}
+	h.CurrentBuilding = nil
+}
+
+// releaseHousing выписывает умершего из квартиры. Квартира умершего владельца переходит родственнику,
+// который в ней живет, иначе возвращается администрации (освобождает место в доме)
+func (h *Human) releaseHousing() {
+	home := h.ResidentialBuilding
+	if home == nil {
+		return
+	}
+
+	if home.IsOwner(h) {
+		if relative := h.relativeIn(home); relative != nil {
+			home.TransferApartment(h, relative)
+			return
+		}
+		home.Mu.Lock()
+		delete(home.Owners, h)
+		if home.Occupied > 0 {
+			home.Occupied--
+		}
+		home.Mu.Unlock()
+	}
+
+	home.Mu.Lock()
+	delete(home.Residents, h)
+	home.Mu.Unlock()
+	h.ResidentialBuilding = nil
+}
+
+// hasRelativesIn сообщает, живет ли в квартире кто-то из родственников
+func (h *Human) hasRelativesIn(home *Building) bool {
+	return h.relativeIn(home) != nil
+}
+
+// relativeIn возвращает живого родственника, который живет в квартире: супруга, затем детей, затем родителей.
+// Родственник, умерший в этот же час, пропускается: он выписывается сам
+func (h *Human) relativeIn(home *Building) *Human {
+	home.Mu.RLock()
+	defer home.Mu.RUnlock()
+
+	for _, relatives := range []map[*Human]float64{h.Family, h.Children, h.Parents} {
+		for _, relative := range sortedHumans(relatives) {
+			if !relative.Dead && home.Residents[relative] {
+				return relative
+			}
+		}
+	}
+	return nil
+}
+
+// widowSpouse делает супруга умершего вдовцом
+func (h *Human) widowSpouse() {
+	spouse := h.Spouse
+	if h.MaritalStatus != Married || spouse == nil || spouse.Spouse != h {
+		return
+	}
+
+	spouse.MaritalStatus = Widowed
+	spouse.Spouse = nil
+	spouse.SpouseBond = 0
+	delete(spouse.Family, h)
+}
@@ -0,0 +1,58 @@
+package components
+
+import "sort"
+
+// Вспомогательные функции детерминированного порядка обхода.
+// Порядок обхода карт в Go случаен, поэтому везде, где от порядка зависит
+// выбор случайного кандидата или распределение ресурсов, используется сортировка.
+
+// sortedHumans возвращает людей из карты отношений, упорядоченных по ID
+func sortedHumans(m map[*Human]float64) []*Human {
+	humans := make([]*Human, 0, len(m))
+	for human := range m {
+		humans = append(humans, human)
+	}
+	sort.Slice(humans, func(i, j int) bool {
+		return GlobalHumanStorage.Get(humans[i]) < GlobalHumanStorage.Get(humans[j])
+	})
+	return humans
+}
+
+// sortBuildings упорядочивает здания по городу и ID
+func sortBuildings(buildings []*Building) {
+	sort.Slice(buildings, func(i, j int) bool {
+		if buildings[i].Location != buildings[j].Location {
+			return buildings[i].Location.Name < buildings[j].Location.Name
+		}
+		return buildings[i].ID < buildings[j].ID
+	})
+}
+
+// sortVacancies упорядочивает вакансии по зданию и ID
+func sortVacancies(vacancies []*Vacancy) {
+	sort.Slice(vacancies, func(i, j int) bool {
+		bi, bj := vacancies[i].Parent.Building, vacancies[j].Parent.Building
+		if bi != bj {
+			if bi.Location != bj.Location {
+				return bi.Location.Name < bj.Location.Name
+			}
+			return bi.ID < bj.ID
+		}
+		return vacancies[i].ID < vacancies[j].ID
+	})
+}
+
+// sortGlobalTargets упорядочивает глобальные цели по имени
+func sortGlobalTargets(targets []*GlobalTarget) {
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Name < targets[j].Name })
+}
+
+// sortLocalTargets упорядочивает локальные цели по имени
+func sortLocalTargets(targets []*LocalTarget) {
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Name < targets[j].Name })
+}
+
+// sortActions упорядочивает действия по имени
+func sortActions(actions []*Action) {
+	sort.Slice(actions, func(i, j int) bool { return actions[i].Name < actions[j].Name })
+}
@@ -0,0 +1,208 @@
+package components
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/fallra1n/humanity/src/config"
+)
+
+// Планировщики решают, чем человек займется в свободный час.
+// Планировщик только выбирает; выполнение действия и учет продвижения по целям - в performActions.
+// Выбор делается в параллельной фазе, поэтому планировщик может читать только состояние самого человека
+
+// Plan - выбранное действие и цели, ради которых оно выполняется.
+// Action может быть nil: локальная цель уже выполнена (например, в рамках другой глобальной цели)
+type Plan struct {
+	Global *GlobalTarget
+	Local  *LocalTarget
+	Action *Action
+}
+
+// Planner выбирает план для человека (nil - делать нечего)
+type Planner interface {
+	Plan(person *Human) *Plan
+}
+
+// planners - известные планировщики по именам из сценария (planner.type)
+var planners = map[string]Planner{
+	"targets": TargetPlanner{},
+	"utility": UtilityPlanner{},
+}
+
+// CurrentPlanner - планировщик текущей симуляции
+var CurrentPlanner Planner = TargetPlanner{}
+
+// SetPlanner выбирает планировщик по имени
+func SetPlanner(name string) error {
+	planner, ok := planners[name]
+	if !ok {
+		return fmt.Errorf("unknown planner %q", name)
+	}
+	CurrentPlanner = planner
+	return nil
+}
+
+// TargetPlanner - исходная логика: выбрать глобальную цель по всплескам или по выполнимости и силе,
+// затем лучшую локальную цель и лучшее действие в ней
+type TargetPlanner struct{}
+
+// Plan выбирает план через глобальную и локальную цель
+func (TargetPlanner) Plan(h *Human) *Plan {
+	if len(h.GlobalTargets) == 0 {
+		return nil
+	}
+
+	rating := make(map[float64][]*GlobalTarget)
+
+	if len(h.Splashes) > 0 {
+		// Оценить цели на основе силы совпадающих всплесков
+		for target := range h.GlobalTargets {
+			rate := (target.Power * h.splashStrength(target.Tags)) / float64(len(h.Splashes))
+			rating[rate] = append(rating[rate], target)
+		}
+	} else {
+		// Оценить цели на основе выполнимости и силы
+		for target := range h.GlobalTargets {
+			var executable float64 = 0
+			if target.Executable(h) {
+				executable = 1
+			}
+			rate := executable * target.Power
+			rating[rate] = append(rating[rate], target)
+		}
+	}
+
+	// Получить цели с наивысшим рейтингом
+	var maxRate float64 = -1
+	for rate := range rating {
+		if rate > maxRate {
+			maxRate = rate
+		}
+	}
+
+	candidates := rating[maxRate]
+	sortGlobalTargets(candidates)
+	global := candidates[h.Rand.NextInt(len(candidates))]
+
+	local := global.ChooseTarget(h)
+	if local == nil {
+		return nil
+	}
+	return &Plan{Global: global, Local: local, Action: local.ChooseAction(h)}
+}
+
+// UtilityPlanner оценивает каждое доступное действие всех целей человека и выбирает самое полезное:
+//
+//	полезность = вес_прогресса * сила_цели * срочность * доля_закрытых_тегов
+//	           - вес_цены * цена / деньги - вес_времени * log10(1 + часы)
+//
+// Доля закрытых тегов - сколько оставшихся тегов локальной цели закрывает действие,
+// срочность - 1 + суммарная сила всплесков, совпадающих с тегами глобальной цели, на один всплеск.
+// Если ни одно действие не полезнее безделья (полезность 0), человек отдыхает
+type UtilityPlanner struct{}
+
+// Plan выбирает действие с наибольшей полезностью
+func (UtilityPlanner) Plan(h *Human) *Plan {
+	globals := make([]*GlobalTarget, 0, len(h.GlobalTargets))
+	for target := range h.GlobalTargets {
+		globals = append(globals, target)
+	}
+	sortGlobalTargets(globals)
+
+	var best []*Plan
+	bestUtility := 0.0
+
+	for _, global := range globals {
+		urgency := 1.0
+		if len(h.Splashes) > 0 {
+			urgency += h.splashStrength(global.Tags) / float64(len(h.Splashes))
+		}
+
+		for _, local := range global.possibleTargets() {
+			// Локальная цель уже выполнена в рамках другой глобальной цели - засчитать без действия
+			if local.IsExecutedFull(h) {
+				return &Plan{Global: global, Local: local}
+			}
+
+			executed := local.executed(h)
+			leftTags := make(map[string]bool)
+			for tag := range local.Tags {
+				leftTags[tag] = true
+			}
+			for action := range executed {
+				for tag := range action.Tags {
+					delete(leftTags, tag)
+				}
+			}
+
+			for _, action := range local.possibleActions() {
+				if executed[action] || !action.Executable(h) {
+					continue
+				}
+
+				closed := 0
+				for tag := range action.Tags {
+					if leftTags[tag] {
+						closed++
+					}
+				}
+				if closed == 0 {
+					continue
+				}
+
+				utility := actionUtility(h, action, global.Power*urgency, float64(closed)/float64(len(leftTags)))
+				switch {
+				case utility > bestUtility:
+					bestUtility = utility
+					best = []*Plan{{Global: global, Local: local, Action: action}}
+				case utility == bestUtility && len(best) > 0:
+					best = append(best, &Plan{Global: global, Local: local, Action: action})
+				}
+			}
+		}
+	}
+
+	if len(best) == 0 {
+		return nil
+	}
+	return best[h.Rand.NextInt(len(best))]
+}
+
+// actionUtility оценивает полезность действия для человека
+func actionUtility(h *Human, action *Action, power, progress float64) float64 {
+	utility := config.UtilityProgressWeight * power * progress
+
+	if cost := action.Price - action.BonusMoney; cost > 0 {
+		money := math.Max(float64(h.Money), 1)
+		utility -= config.UtilityCostWeight * float64(cost) / money
+	}
+	if action.TimeToExecute > 0 {
+		utility -= config.UtilityTimeWeight * math.Log10(1+float64(action.TimeToExecute))
+	}
+
+	return utility
+}
+
+// possibleTargets возвращает еще не выполненные локальные цели в детерминированном порядке
+func (gt *GlobalTarget) possibleTargets() []*LocalTarget {
+	gt.Mu.RLock()
+	defer gt.Mu.RUnlock()
+
+	targets := make([]*LocalTarget, 0, len(gt.TargetsPossible))
+	for target := range gt.TargetsPossible {
+		targets = append(targets, target)
+	}
+	sortLocalTargets(targets)
+	return targets
+}
+
+// possibleActions возвращает действия цели в детерминированном порядке
+func (lt *LocalTarget) possibleActions() []*Action {
+	actions := make([]*Action, 0, len(lt.ActionsPossible))
+	for action := range lt.ActionsPossible {
+		actions = append(actions, action)
+	}
+	sortActions(actions)
+	return actions
+}
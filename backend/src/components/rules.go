@@ -0,0 +1,570 @@
+package components
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/fallra1n/humanity/src/utils"
+)
+
+// Язык условий действий (правила $ в actions.ini).
+//
+//	условие   := или
+//	или       := и { "or" и }
+//	и         := не { "and" не }
+//	не        := "not" не | "(" условие ")" | сравнение | операнд
+//	сравнение := операнд ( ">" | "<" | "=" | "<>" | ">=" | "<=" ) операнд
+//	операнд   := число | "строка" | метрика | item(предмет) | attr(атрибут) | предмет
+//
+// Метрики перечислены в ruleMetrics. Предмет вне сравнения означает его наличие у человека,
+// item(предмет) - количество, attr(атрибут) - атрибут, заданный эффектом !set. В сравнении слово без кавычек должно быть метрикой,
+// кроме сравнения со строковой метрикой (gender, city): gender=female
+
+// ruleKind - тип значения в условии
+type ruleKind int
+
+const (
+	ruleNumber ruleKind = iota
+	ruleBool
+	ruleString
+)
+
+func (k ruleKind) String() string {
+	switch k {
+	case ruleBool:
+		return "boolean"
+	case ruleString:
+		return "string"
+	default:
+		return "number"
+	}
+}
+
+// ruleValue - значение операнда условия
+type ruleValue struct {
+	num float64 // для чисел и логических значений (1 - истина)
+	str string
+}
+
+// ruleMetric описывает метрику человека, доступную в условиях
+type ruleMetric struct {
+	kind  ruleKind
+	value func(person *Human) ruleValue
+}
+
+// ruleMetrics - метрики, доступные в условиях действий
+var ruleMetrics = map[string]ruleMetric{
+	// Личные деньги и общий бюджет домохозяйства (бюджет меняется только в последовательной фазе)
+	"cash": {ruleNumber, func(person *Human) ruleValue {
+		cash := person.Money
+		if person.Household != nil {
+			cash += person.Household.Budget
+		}
+		return ruleValue{num: float64(cash)}
+	}},
+	"job_time": {ruleNumber, func(person *Human) ruleValue {
+		return ruleValue{num: float64(person.JobTime)}
+	}},
+	"age": {ruleNumber, func(person *Human) ruleValue {
+		return ruleValue{num: person.Age}
+	}},
+	"gender": {ruleString, func(person *Human) ruleValue {
+		return ruleValue{str: string(person.Gender)}
+	}},
+	"married": {ruleBool, func(person *Human) ruleValue {
+		return boolValue(person.MaritalStatus == Married)
+	}},
+	"children": {ruleNumber, func(person *Human) ruleValue {
+		return ruleValue{num: float64(len(person.Children))}
+	}},
+	"salary": {ruleNumber, func(person *Human) ruleValue {
+		if person.Job == nil {
+			return ruleValue{}
+		}
+		return ruleValue{num: float64(person.Job.Payment)}
+	}},
+	"friends": {ruleNumber, func(person *Human) ruleValue {
+		return ruleValue{num: float64(len(person.Friends))}
+	}},
+	"city": {ruleString, func(person *Human) ruleValue {
+		if person.HomeLocation == nil {
+			return ruleValue{}
+		}
+		return ruleValue{str: person.HomeLocation.Name}
+	}},
+	"hour_of_day": {ruleNumber, func(person *Human) ruleValue {
+		return ruleValue{num: float64(utils.GetHourOfDay(utils.GlobalTick.Get()))}
+	}},
+}
+
+func boolValue(b bool) ruleValue {
+	if b {
+		return ruleValue{num: 1}
+	}
+	return ruleValue{}
+}
+
+// Rule - разобранное условие действия
+type Rule struct {
+	Source string
+	root   ruleNode
+}
+
+// ParseRule разбирает условие (без префикса $)
+func ParseRule(source string) (*Rule, error) {
+	tokens, err := tokenizeRule(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &ruleParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.unexpected(tok)
+	}
+	if root.kind() == ruleString {
+		return nil, fmt.Errorf("rule %q is a string, not a condition", source)
+	}
+	return &Rule{Source: source, root: root}, nil
+}
+
+// Eval проверяет условие для человека
+func (r *Rule) Eval(person *Human) bool {
+	return r.root.eval(person).num != 0
+}
+
+// String возвращает исходный текст условия
+func (r *Rule) String() string {
+	return r.Source
+}
+
+// Items возвращает предметы, упомянутые в условии (без повторов, в порядке записи)
+func (r *Rule) Items() []string {
+	var items []string
+	seen := make(map[string]bool)
+
+	var walk func(node ruleNode)
+	walk = func(node ruleNode) {
+		switch n := node.(type) {
+		case *itemNode:
+			if !seen[n.name] {
+				seen[n.name] = true
+				items = append(items, n.name)
+			}
+		case *compareNode:
+			walk(n.left)
+			walk(n.right)
+		case *logicNode:
+			walk(n.left)
+			walk(n.right)
+		case *notNode:
+			walk(n.operand)
+		}
+	}
+	walk(r.root)
+
+	return items
+}
+
+// Узлы дерева условия
+
+type ruleNode interface {
+	kind() ruleKind
+	eval(person *Human) ruleValue
+}
+
+type literalNode struct {
+	k     ruleKind
+	value ruleValue
+}
+
+func (n *literalNode) kind() ruleKind               { return n.k }
+func (n *literalNode) eval(person *Human) ruleValue { return n.value }
+
+type metricNode struct {
+	metric ruleMetric
+}
+
+func (n *metricNode) kind() ruleKind               { return n.metric.kind }
+func (n *metricNode) eval(person *Human) ruleValue { return n.metric.value(person) }
+
+// itemNode - количество предмета; presence - вместо количества проверить наличие
+type itemNode struct {
+	name     string
+	bare     bool // записан словом без item(...)
+	presence bool
+}
+
+func (n *itemNode) kind() ruleKind {
+	if n.presence {
+		return ruleBool
+	}
+	return ruleNumber
+}
+
+func (n *itemNode) eval(person *Human) ruleValue {
+	if n.presence {
+		return boolValue(person.Items[n.name] > 0)
+	}
+	return ruleValue{num: float64(person.Items[n.name])}
+}
+
+// attrNode - атрибут человека (не заданный атрибут равен 0)
+type attrNode struct {
+	name string
+}
+
+func (n *attrNode) kind() ruleKind { return ruleNumber }
+
+func (n *attrNode) eval(person *Human) ruleValue {
+	return ruleValue{num: person.Attributes[n.name]}
+}
+
+type compareNode struct {
+	op          string
+	left, right ruleNode
+}
+
+func (n *compareNode) kind() ruleKind { return ruleBool }
+
+func (n *compareNode) eval(person *Human) ruleValue {
+	a, b := n.left.eval(person), n.right.eval(person)
+	if n.left.kind() == ruleString {
+		if n.op == "=" {
+			return boolValue(a.str == b.str)
+		}
+		return boolValue(a.str != b.str)
+	}
+
+	switch n.op {
+	case "=":
+		return boolValue(a.num == b.num)
+	case "<>":
+		return boolValue(a.num != b.num)
+	case ">":
+		return boolValue(a.num > b.num)
+	case "<":
+		return boolValue(a.num < b.num)
+	case ">=":
+		return boolValue(a.num >= b.num)
+	default:
+		return boolValue(a.num <= b.num)
+	}
+}
+
+type logicNode struct {
+	op          string // and, or
+	left, right ruleNode
+}
+
+func (n *logicNode) kind() ruleKind { return ruleBool }
+
+func (n *logicNode) eval(person *Human) ruleValue {
+	left := n.left.eval(person).num != 0
+	if n.op == "and" && !left || n.op == "or" && left {
+		return boolValue(left)
+	}
+	return boolValue(n.right.eval(person).num != 0)
+}
+
+type notNode struct {
+	operand ruleNode
+}
+
+func (n *notNode) kind() ruleKind { return ruleBool }
+
+func (n *notNode) eval(person *Human) ruleValue {
+	return boolValue(n.operand.eval(person).num == 0)
+}
+
+// Лексический анализ
+
+type ruleTokenKind int
+
+const (
+	tokEOF ruleTokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOperator
+	tokLParen
+	tokRParen
+)
+
+type ruleToken struct {
+	kind ruleTokenKind
+	text string
+	pos  int // позиция в исходной строке (с 1)
+}
+
+func tokenizeRule(source string) ([]ruleToken, error) {
+	var tokens []ruleToken
+	runes := []rune(source)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '(':
+			tokens = append(tokens, ruleToken{tokLParen, "(", start + 1})
+			i++
+		case r == ')':
+			tokens = append(tokens, ruleToken{tokRParen, ")", start + 1})
+			i++
+		case r == '"':
+			i++
+			for i < len(runes) && runes[i] != '"' {
+				i++
+			}
+			if i == len(runes) {
+				return nil, fmt.Errorf("unterminated string at column %d", start+1)
+			}
+			tokens = append(tokens, ruleToken{tokString, string(runes[start+1 : i]), start + 1})
+			i++
+		case r == '<' || r == '>' || r == '=':
+			i++
+			if i < len(runes) && (runes[i] == '=' || r == '<' && runes[i] == '>') && r != '=' {
+				i++
+			}
+			tokens = append(tokens, ruleToken{tokOperator, string(runes[start:i]), start + 1})
+		case unicode.IsDigit(r) || r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1]):
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, ruleToken{tokNumber, string(runes[start:i]), start + 1})
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '-') {
+				i++
+			}
+			tokens = append(tokens, ruleToken{tokIdent, string(runes[start:i]), start + 1})
+		default:
+			return nil, fmt.Errorf("unexpected character %q at column %d", r, start+1)
+		}
+	}
+
+	return append(tokens, ruleToken{tokEOF, "", len(runes) + 1}), nil
+}
+
+// Синтаксический анализ (рекурсивный спуск)
+
+type ruleParser struct {
+	tokens []ruleToken
+	pos    int
+}
+
+func (p *ruleParser) peek() ruleToken {
+	return p.tokens[p.pos]
+}
+
+func (p *ruleParser) next() ruleToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *ruleParser) isKeyword(word string) bool {
+	tok := p.peek()
+	return tok.kind == tokIdent && tok.text == word
+}
+
+func (p *ruleParser) unexpected(tok ruleToken) error {
+	if tok.kind == tokEOF {
+		return fmt.Errorf("unexpected end of rule")
+	}
+	return fmt.Errorf("unexpected %q at column %d", tok.text, tok.pos)
+}
+
+func (p *ruleParser) parseOr() (ruleNode, error) {
+	return p.parseLogic("or", p.parseAnd)
+}
+
+func (p *ruleParser) parseAnd() (ruleNode, error) {
+	return p.parseLogic("and", p.parseNot)
+}
+
+// parseLogic разбирает цепочку операндов, соединенных логическим оператором op
+func (p *ruleParser) parseLogic(op string, operand func() (ruleNode, error)) (ruleNode, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword(op) {
+		tok := p.next()
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		if err := requireCondition(left, tok); err != nil {
+			return nil, err
+		}
+		if err := requireCondition(right, tok); err != nil {
+			return nil, err
+		}
+		left = &logicNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *ruleParser) parseNot() (ruleNode, error) {
+	if p.isKeyword("not") {
+		tok := p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if err := requireCondition(operand, tok); err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+
+	if p.peek().kind == tokLParen {
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.kind != tokRParen {
+			return nil, p.unexpected(tok)
+		}
+		return inner, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *ruleParser) parseComparison() (ruleNode, error) {
+	leftTok := p.peek()
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek().kind != tokOperator {
+		// Предмет без сравнения - проверка наличия
+		if item, ok := left.(*itemNode); ok && item.bare {
+			item.presence = true
+		}
+		return left, nil
+	}
+
+	opTok := p.next()
+	rightTok := p.peek()
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	// Слово без кавычек рядом со строковой метрикой - строковое значение
+	if left.kind() == ruleString {
+		right = asStringLiteral(right, rightTok)
+	}
+	if right.kind() == ruleString {
+		left = asStringLiteral(left, leftTok)
+	}
+
+	// В сравнении слово должно быть метрикой: опечатка не должна молча давать 0
+	for _, side := range []struct {
+		node ruleNode
+		tok  ruleToken
+	}{{left, leftTok}, {right, rightTok}} {
+		if item, ok := side.node.(*itemNode); ok && item.bare {
+			return nil, fmt.Errorf("unknown metric %q at column %d (use item(%s) to compare item counts)", item.name, side.tok.pos, item.name)
+		}
+	}
+
+	if (left.kind() == ruleString) != (right.kind() == ruleString) {
+		return nil, fmt.Errorf("cannot compare %s with %s at column %d", left.kind(), right.kind(), opTok.pos)
+	}
+	if left.kind() == ruleString && opTok.text != "=" && opTok.text != "<>" {
+		return nil, fmt.Errorf("operator %q is not defined for strings at column %d", opTok.text, opTok.pos)
+	}
+	return &compareNode{op: opTok.text, left: left, right: right}, nil
+}
+
+func (p *ruleParser) parseOperand() (ruleNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokNumber:
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at column %d", tok.text, tok.pos)
+		}
+		return &literalNode{k: ruleNumber, value: ruleValue{num: value}}, nil
+	case tokString:
+		return &literalNode{k: ruleString, value: ruleValue{str: tok.text}}, nil
+	case tokIdent:
+		if tok.text == "and" || tok.text == "or" || tok.text == "not" {
+			return nil, p.unexpected(tok)
+		}
+		if metric, ok := ruleMetrics[tok.text]; ok {
+			return &metricNode{metric: metric}, nil
+		}
+		if (tok.text == "item" || tok.text == "attr") && p.peek().kind == tokLParen {
+			p.next()
+			name := p.next()
+			if name.kind != tokIdent {
+				return nil, p.unexpected(name)
+			}
+			if closing := p.next(); closing.kind != tokRParen {
+				return nil, p.unexpected(closing)
+			}
+			if tok.text == "attr" {
+				return &attrNode{name: name.text}, nil
+			}
+			return &itemNode{name: name.text}, nil
+		}
+		return &itemNode{name: tok.text, bare: true}, nil
+	default:
+		return nil, p.unexpected(tok)
+	}
+}
+
+// asStringLiteral превращает слово без кавычек в строковое значение
+func asStringLiteral(node ruleNode, tok ruleToken) ruleNode {
+	if item, ok := node.(*itemNode); ok && item.bare {
+		return &literalNode{k: ruleString, value: ruleValue{str: item.name}}
+	}
+	return node
+}
+
+// requireCondition проверяет, что операнд логического оператора - условие, а не строка
+func requireCondition(node ruleNode, op ruleToken) error {
+	if node.kind() == ruleString {
+		return fmt.Errorf("operand of %q at column %d is a string, not a condition", op.text, op.pos)
+	}
+	return nil
+}
+
+// SplitRuleWords объединяет слова строки, относящиеся к одному правилу:
+// правило со скобками или кавычками может содержать пробелы
+func SplitRuleWords(words []string) (rule string, rest []string) {
+	depth, quoted := 0, false
+	for i, word := range words {
+		for _, r := range word {
+			switch {
+			case r == '"':
+				quoted = !quoted
+			case r == '(' && !quoted:
+				depth++
+			case r == ')' && !quoted:
+				depth--
+			}
+		}
+		if depth <= 0 && !quoted {
+			return strings.Join(words[:i+1], " "), words[i+1:]
+		}
+	}
+	return strings.Join(words, " "), nil
+}
@@ -0,0 +1,117 @@
+package components
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRuleEval(t *testing.T) {
+	person := NewHuman(map[*Human]bool{}, nil, nil)
+	person.Age = 30
+	person.Gender = Female
+	person.Money = 500
+	person.Items["car"] = 2
+	person.Attributes["mood"] = 0.5
+
+	tests := []struct {
+		rule string
+		want bool
+	}{
+		{"age>18", true},
+		{"age >= 30", true},
+		{"age<30", false},
+		{"age<>30", false},
+		{"cash=500", true},
+		{"car", true},
+		{"house", false},
+		{"not house", true},
+		{"item(car)>1", true},
+		{"item(house)=0", true},
+		{"attr(mood)>0.4", true},
+		{"attr(missing)=0", true},
+		{"gender=female", true},
+		{`gender="male"`, false},
+		{"female=gender", true},
+		{"married", false},
+		{"age>18 and not married", true},
+		{"married or car", true},
+		{"married or house and car", false},
+		{"(married or car) and age>-1", true},
+		{"not (car and age>40)", true},
+		{"not not car", true},
+	}
+
+	for _, tt := range tests {
+		rule, err := ParseRule(tt.rule)
+		if err != nil {
+			t.Errorf("ParseRule(%q): %v", tt.rule, err)
+			continue
+		}
+		if got := rule.Eval(person); got != tt.want {
+			t.Errorf("%q: Eval = %v, want %v", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestParseRuleErrors(t *testing.T) {
+	tests := []struct {
+		rule string
+		err  string // фрагмент текста ошибки
+	}{
+		{"", "unexpected end of rule"},
+		{"age>", "unexpected end of rule"},
+		{"(age>18", "unexpected end of rule"},
+		{"age>18)", `unexpected ")" at column 7`},
+		{"age>18 car", `unexpected "car" at column 8`},
+		{"and car", `unexpected "and" at column 1`},
+		{`"abc`, "unterminated string at column 1"},
+		{"age>18 & car", `unexpected character '&' at column 8`},
+		{"cars>1", `unknown metric "cars" at column 1`},
+		{"gender", "is a string, not a condition"},
+		{"gender and car", "is a string, not a condition"},
+		{"gender>male", `operator ">" is not defined for strings`},
+		{"gender=1", "cannot compare string with number"},
+		{"item(1)>0", `unexpected "1" at column 6`},
+		{"age>1.2.3", `invalid number "1.2.3"`},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseRule(tt.rule)
+		if err == nil {
+			t.Errorf("ParseRule(%q): expected error containing %q", tt.rule, tt.err)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("ParseRule(%q) = %q, want error containing %q", tt.rule, err, tt.err)
+		}
+	}
+}
+
+func TestRuleItems(t *testing.T) {
+	rule, err := ParseRule("car and (item(house)>0 or not car) and attr(mood)>0 and age>18")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(rule.Items(), ","); got != "car,house" {
+		t.Errorf("Items = %s, want car,house", got)
+	}
+}
+
+func TestSplitRuleWords(t *testing.T) {
+	tests := []struct {
+		words []string
+		rule  string
+		rest  string
+	}{
+		{[]string{"age>18", "10", "2"}, "age>18", "10 2"},
+		{[]string{"(age>18", "and", "car)", "10"}, "(age>18 and car)", "10"},
+		{[]string{`city="New`, `York"`, "5"}, `city="New York"`, "5"},
+	}
+
+	for _, tt := range tests {
+		rule, rest := SplitRuleWords(tt.words)
+		if rule != tt.rule || strings.Join(rest, " ") != tt.rest {
+			t.Errorf("SplitRuleWords(%q) = %q, %q; want %q, %q", tt.words, rule, rest, tt.rule, tt.rest)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/fallra1n/humanity/src/config"
+)
+
+// Всплески, которые создает сама симуляция, описываются в splashes.ini:
+// имя, время жизни, теги, начальная сила и затухание.
+// Время жизни некоторых всплесков задает сценарий (splashLifetimes), в splashes.ini вместо него пишется -.
+// Код создает их по имени через EmitSplash
+
+// Всплески, которые создает симуляция (должны быть описаны в splashes.ini)
+const (
+	SplashNeedMoney         = "need_money"
+	SplashCareerAdvancement = "career_advancement"
+	SplashJobLoss           = "job_loss"
+	SplashDivorce           = "divorce"
+	SplashPregnancy         = "pregnancy"
+	SplashChildBirth        = "child_birth"
+)
+
+// RequiredSplashes - всплески, без описания которых симуляция не запускается
+var RequiredSplashes = []string{
+	SplashNeedMoney,
+	SplashCareerAdvancement,
+	SplashJobLoss,
+	SplashDivorce,
+	SplashPregnancy,
+	SplashChildBirth,
+}
+
+// splashLifetimes - всплески, время жизни которых задает сценарий, а не splashes.ini
+var splashLifetimes = map[string]func() uint64{
+	SplashPregnancy: func() uint64 { return config.PregnancyDurationHours },
+}
+
+// SplashDefinition - описание всплеска из splashes.ini
+type SplashDefinition struct {
+	Name             string
+	Tags             []string
+	Lifetime         uint64
+	ScenarioLifetime bool // время жизни задает сценарий (в splashes.ini вместо времени жизни -)
+	Intensity        float64
+	HalfLife         uint64 // 0 - сила не убывает
+}
+
+// splashDefinitions - описания всплесков текущей симуляции
+var splashDefinitions = map[string]*SplashDefinition{}
+
+// SetSplashDefinitions устанавливает описания всплесков и проверяет, что описаны все нужные симуляции
+func SetSplashDefinitions(definitions []*SplashDefinition) error {
+	byName := make(map[string]*SplashDefinition)
+	for _, definition := range definitions {
+		if _, exists := byName[definition.Name]; exists {
+			return fmt.Errorf("splash name duplication: %s", definition.Name)
+		}
+		byName[definition.Name] = definition
+
+		_, fromScenario := splashLifetimes[definition.Name]
+		if definition.ScenarioLifetime && !fromScenario {
+			return fmt.Errorf("splash %s: lifetime is not set", definition.Name)
+		}
+		if !definition.ScenarioLifetime && fromScenario {
+			return fmt.Errorf("splash %s: lifetime is set by the scenario, use - instead of %d", definition.Name, definition.Lifetime)
+		}
+	}
+
+	for _, name := range RequiredSplashes {
+		if _, exists := byName[name]; !exists {
+			return fmt.Errorf("splash %s is not defined", name)
+		}
+	}
+
+	splashDefinitions = byName
+	return nil
+}
+
+// NewSplash создает всплеск по описанию
+func (d *SplashDefinition) NewSplash() *Splash {
+	lifetime := d.Lifetime
+	if d.ScenarioLifetime {
+		lifetime = splashLifetimes[d.Name]()
+	}
+	splash := NewSplash(d.Name, d.Tags, lifetime)
+	splash.Intensity = d.Intensity
+	splash.HalfLife = d.HalfLife
+	return splash
+}
+
+// EmitSplash создает у человека всплеск, описанный в splashes.ini
+func (h *Human) EmitSplash(name string) {
+	definition, exists := splashDefinitions[name]
+	if !exists {
+		return
+	}
+	h.Splashes = append(h.Splashes, definition.NewSplash())
+}
+
+// splashStrength суммирует силу всплесков, хотя бы один тег которых есть среди тегов
+func (h *Human) splashStrength(tags map[string]bool) float64 {
+	strength := 0.0
+	for _, splash := range h.Splashes {
+		for tag := range splash.Tags {
+			if tags[tag] {
+				strength += splash.Strength()
+				break
+			}
+		}
+	}
+	return strength
+}
@@ -1,12 +1,16 @@
 package components
 
 import (
-	"github.com/fallra1n/humanity/src/utils"
+	"math"
 	"sync"
+
+	"github.com/fallra1n/humanity/src/config"
+	"github.com/fallra1n/humanity/src/utils"
 )
 
 // Vacancy представляет вакансию
 type Vacancy struct {
+	ID           int // Уникален в пределах города
 	Parent       *Job
 	RequiredTags map[string]bool
 	Payment      int
@@ -44,9 +48,11 @@ type Splash struct {
 	Tags       map[string]bool
 	AppearTime uint64
 	LifeLength uint64
+	Intensity  float64 // Начальная сила всплеска
+	HalfLife   uint64  // Период полураспада силы в часах (0 - сила не убывает)
 }
 
-// NewSplash создает новый всплеск
+// NewSplash создает новый всплеск силы 1, которая не убывает
 func NewSplash(name string, tags []string, lifeLength uint64) *Splash {
 	tagSet := make(map[string]bool)
 	for _, tag := range tags {
@@ -58,10 +64,23 @@ func NewSplash(name string, tags []string, lifeLength uint64) *Splash {
 		Tags:       tagSet,
 		AppearTime: utils.GlobalTick.Get(),
 		LifeLength: lifeLength,
+		Intensity:  1.0,
 	}
 }
 
-// IsExpired проверяет, истек ли всплеск
+// Strength возвращает текущую силу всплеска с учетом затухания
+func (s *Splash) Strength() float64 {
+	if s.HalfLife == 0 {
+		return s.Intensity
+	}
+	age := float64(utils.GlobalTick.Get() - s.AppearTime)
+	return s.Intensity * math.Pow(0.5, age/float64(s.HalfLife))
+}
+
+// IsExpired проверяет, истек ли всплеск: прошло время жизни или затухающий всплеск ослаб
 func (s *Splash) IsExpired() bool {
-	return utils.GlobalTick.Get()-s.AppearTime > s.LifeLength
+	if utils.GlobalTick.Get()-s.AppearTime > s.LifeLength {
+		return true
+	}
+	return s.HalfLife > 0 && s.Strength() < config.MinSplashIntensity
 }
@@ -9,85 +9,98 @@ import (
 	"github.com/fallra1n/humanity/src/utils"
 )
 
-// LoadActions загружает действия из конфигурационного файла
+// LoadActions загружает действия из конфигурационного файла.
+// Ошибки разбора содержат файл и строку: actions.ini:12: ...
 func LoadActions(filename string) ([]*components.Action, error) {
-	sequences, err := utils.LoadSequencesFromFile(filename)
+	sequences, err := utils.LoadNumberedSequencesFromFile(filename)
 	if err != nil {
 		return nil, err
 	}
 
 	var actions []*components.Action
 
-	for _, words := range sequences {
-		if len(words) < 4 {
-			return nil, fmt.Errorf("invalid action format in %s", filename)
-		}
-
-		name := words[0]
-		price, err := strconv.ParseInt(words[1], 10, 64)
+	for _, sequence := range sequences {
+		action, err := parseAction(fmt.Sprintf("%s:%d", filename, sequence.Line), sequence.Words)
 		if err != nil {
-			return nil, fmt.Errorf("invalid price for action %s: %v", name, err)
+			return nil, err
 		}
+		actions = append(actions, action)
+	}
 
-		timeToExecute, err := strconv.ParseInt(words[2], 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("invalid time for action %s: %v", name, err)
-		}
+	return actions, nil
+}
 
-		var bonusMoney int64 = 0
-		var tags []string
-		rules := make(map[string]int64)
-		items := make(map[string]int64)
-		removableItems := make(map[string]int64)
-
-		for i := 3; i < len(words); i++ {
-			word := words[i]
-
-			if strings.HasPrefix(word, "$") {
-				// Правило
-				if len(word) > 1 && word[1] == '-' {
-					// Удаляемый предмет
-					key := word[2:]
-					if count, exists := removableItems[key]; exists {
-						removableItems[key] = count + 1
-					} else {
-						removableItems[key] = 1
-					}
-				} else {
-					// Обычное правило
-					key := word[1:]
-					if count, exists := rules[key]; exists {
-						rules[key] = count + 1
-					} else {
-						rules[key] = 1
-					}
-				}
-			} else if strings.HasPrefix(word, "@") {
-				// Предмет для добавления
-				key := word[1:]
-				if count, exists := items[key]; exists {
-					items[key] = count + 1
-				} else {
-					items[key] = 1
-				}
-			} else if strings.HasPrefix(word, "+") {
-				// Бонусные деньги
-				bonus, err := strconv.ParseInt(word[1:], 10, 64)
-				if err != nil {
-					return nil, fmt.Errorf("invalid bonus for action %s: %v", name, err)
-				}
-				bonusMoney = bonus
-			} else {
-				// Тег
-				tags = append(tags, word)
+// parseAction разбирает строку actions.ini; where - файл и строка для сообщений об ошибках
+func parseAction(where string, words []string) (*components.Action, error) {
+	if len(words) < 4 {
+		return nil, fmt.Errorf("%s: invalid action format", where)
+	}
+
+	name := words[0]
+	price, err := strconv.ParseInt(words[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid price for action %s: %v", where, name, err)
+	}
+
+	timeToExecute, err := strconv.ParseInt(words[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid time for action %s: %v", where, name, err)
+	}
+
+	var bonusMoney int64 = 0
+	var tags []string
+	var rules []*components.Rule
+	var effects []*components.Effect
+	var buildingType components.BuildingType
+	items := make(map[string]int64)
+	removableItems := make(map[string]int64)
+
+	for rest := words[3:]; len(rest) > 0; {
+		word := rest[0]
+		rest = rest[1:]
+
+		if strings.HasPrefix(word, "$-") {
+			// Удаляемый предмет
+			removableItems[word[2:]]++
+		} else if strings.HasPrefix(word, "$") {
+			// Правило (со скобками или кавычками может занимать несколько слов)
+			var source string
+			source, rest = components.SplitRuleWords(append([]string{word[1:]}, rest...))
+			rule, err := components.ParseRule(source)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid rule $%s for action %s: %v", where, source, name, err)
+			}
+			rules = append(rules, rule)
+		} else if strings.HasPrefix(word, "!") {
+			// Эффект
+			effect, err := components.ParseEffect(word[1:])
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid effect %s for action %s: %v", where, word, name, err)
+			}
+			effects = append(effects, effect)
+		} else if strings.HasPrefix(word, "%") {
+			// Тип здания, в котором выполняется действие
+			buildingType, err = components.ParseActivityBuildingType(word[1:])
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid building %s for action %s: %v", where, word, name, err)
 			}
+		} else if strings.HasPrefix(word, "@") {
+			// Предмет для добавления
+			items[word[1:]]++
+		} else if strings.HasPrefix(word, "+") {
+			// Бонусные деньги
+			bonus, err := strconv.ParseInt(word[1:], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid bonus for action %s: %v", where, name, err)
+			}
+			bonusMoney = bonus
+		} else {
+			// Тег
+			tags = append(tags, word)
 		}
-
-		action := components.NewAction(name, price, timeToExecute, tags, rules, items, removableItems, bonusMoney)
-		actions = append(actions, action)
 	}
 
-	return actions, nil
+	return components.NewAction(name, price, timeToExecute, tags, rules, items, removableItems, bonusMoney, effects, buildingType), nil
 }
 
 // LoadLocalTargets загружает локальные цели из конфигурационного файла
@@ -130,28 +143,108 @@ func LoadGlobalTargets(filename string, allLocalTargets []*components.LocalTarge
 
 		name := words[0]
 		var power float64 = 1.0
+		var weight float64 = 1.0
+		var stages []components.LifeStage
 		var tags []string
 
+		rest := words[1:]
 		// Попытаться разобрать второе слово как силу (float)
-		if len(words) > 2 {
-			if parsedPower, err := strconv.ParseFloat(words[1], 64); err == nil {
+		if len(rest) > 1 {
+			if parsedPower, err := strconv.ParseFloat(rest[0], 64); err == nil {
 				power = parsedPower
-				tags = words[2:]
+				rest = rest[1:]
+			}
+		}
+
+		for _, word := range rest {
+			if strings.HasPrefix(word, "*") {
+				// Вес при назначении цели
+				parsedWeight, err := strconv.ParseFloat(word[1:], 64)
+				if err != nil || parsedWeight < 0 {
+					return nil, fmt.Errorf("invalid weight %s for global target %s in %s", word, name, filename)
+				}
+				weight = parsedWeight
+			} else if strings.HasPrefix(word, "~") {
+				// Этап жизни, на котором назначается цель
+				stage, err := components.ParseLifeStage(word[1:])
+				if err != nil {
+					return nil, fmt.Errorf("invalid life stage %s for global target %s in %s: %v", word, name, filename, err)
+				}
+				stages = append(stages, stage)
 			} else {
-				// Второе слово не число, рассматривать как тег
-				tags = words[1:]
+				// Тег
+				tags = append(tags, word)
 			}
-		} else {
-			tags = words[1:]
 		}
 
-		target := components.NewGlobalTarget(name, tags, power, allLocalTargets)
+		if len(tags) == 0 {
+			return nil, fmt.Errorf("global target %s has no tags in %s", name, filename)
+		}
+
+		target := components.NewGlobalTarget(name, tags, power, weight, stages, allLocalTargets)
 		targets = append(targets, target)
 	}
 
 	return targets, nil
 }
 
+// LoadSplashes загружает описания всплесков из конфигурационного файла.
+// Формат строки: имя время_жизни [*сила] [~период_полураспада] теги...
+// Время жизни - (задается сценарием) проверяется в components.SetSplashDefinitions
+func LoadSplashes(filename string) ([]*components.SplashDefinition, error) {
+	sequences, err := utils.LoadNumberedSequencesFromFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var definitions []*components.SplashDefinition
+
+	for _, sequence := range sequences {
+		words := sequence.Words
+		where := fmt.Sprintf("%s:%d", filename, sequence.Line)
+		if len(words) < 2 {
+			return nil, fmt.Errorf("%s: invalid splash format", where)
+		}
+
+		definition := &components.SplashDefinition{Name: words[0], Intensity: 1.0}
+		if words[1] == "-" {
+			definition.ScenarioLifetime = true
+		} else if definition.Lifetime, err = strconv.ParseUint(words[1], 10, 64); err != nil {
+			return nil, fmt.Errorf("%s: invalid lifetime for splash %s: %v", where, definition.Name, err)
+		}
+
+		for _, word := range words[2:] {
+			if strings.HasPrefix(word, "*") {
+				// Начальная сила
+				intensity, err := strconv.ParseFloat(word[1:], 64)
+				if err != nil || intensity <= 0 {
+					return nil, fmt.Errorf("%s: invalid intensity %s for splash %s", where, word, definition.Name)
+				}
+				definition.Intensity = intensity
+			} else if strings.HasPrefix(word, "~") {
+				// Период полураспада силы
+				halfLife, err := strconv.ParseUint(word[1:], 10, 64)
+				if err != nil || halfLife == 0 {
+					return nil, fmt.Errorf("%s: invalid half-life %s for splash %s", where, word, definition.Name)
+				}
+				definition.HalfLife = halfLife
+			} else {
+				// Тег
+				definition.Tags = append(definition.Tags, word)
+			}
+		}
+
+		// Без тегов тегом служит имя всплеска, как в эффекте !splash
+		if len(definition.Tags) == 0 {
+			definition.Tags = []string{definition.Name}
+		}
+
+		definitions = append(definitions, definition)
+	}
+
+	return definitions, nil
+}
+
 // CreateNameMaps создает карты поиска для действий, локальных целей и глобальных целей
 func CreateNameMaps(actions []*components.Action, localTargets []*components.LocalTarget, globalTargets []*components.GlobalTarget) (
 	map[string]*components.Action, map[string]*components.LocalTarget, map[string]*components.GlobalTarget, error) {
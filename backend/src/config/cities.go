@@ -0,0 +1,156 @@
+package config
+
+// BuildingCoordinate представляет координаты здания
+type BuildingCoordinate struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// CitySpec описывает город: население, цены на жилье и список зданий
+type CitySpec struct {
+	Name           string             `json:"name"`
+	Population     int                `json:"population"`
+	ApartmentPrice int64              `json:"apartment_price"` // цена квартиры у администрации
+	Center         BuildingCoordinate `json:"center"`
+	Buildings      []BuildingSpec     `json:"buildings"`
+}
+
+// BuildingSpec описывает одно здание города.
+// Если имя не задано, оно строится из названия города и типа здания.
+// Если координаты не заданы, здание ставится в центр города
+type BuildingSpec struct {
+	Type     string    `json:"type"`
+	Name     string    `json:"name,omitempty"`
+	Capacity int       `json:"capacity"`
+	Lat      float64   `json:"lat,omitempty"`
+	Lon      float64   `json:"lon,omitempty"`
+	Jobs     []JobSpec `json:"jobs,omitempty"` // только для рабочих мест
+}
+
+// JobSpec описывает работу в рабочем здании
+type JobSpec struct {
+	Vacancies []VacancySpec `json:"vacancies"`
+}
+
+// VacancySpec описывает вакансию: зарплата и число позиций выбираются случайно из диапазонов
+type VacancySpec struct {
+	Salary       IntRange `json:"salary"`    // рубли/месяц
+	Positions    IntRange `json:"positions"` // количество позиций
+	RequiredTags []string `json:"required_tags,omitempty"`
+}
+
+// PathSpec описывает дорогу из одного города в другой
+type PathSpec struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Price uint64 `json:"price"` // стоимость переезда в рублях на человека
+	Time  uint64 `json:"time"`  // время в пути в часах
+}
+
+// BuildingTypes - допустимые типы зданий
+var BuildingTypes = []string{
+	"hospital",
+	"school",
+	"workplace",
+	"entertainment",
+	"cafe",
+	"shop",
+	"residential_house",
+}
+
+// Cities - города симуляции
+var Cities = defaultCities()
+
+// Paths - дороги между городами (однонаправленные)
+var Paths = []PathSpec{
+	{From: "City 1", To: "City 2", Price: 500, Time: 2},
+	{From: "City 2", To: "City 1", Price: 500, Time: 2},
+}
+
+// defaultCities возвращает два города по умолчанию:
+// малый (40% населения) и большой (60% населения)
+func defaultCities() []CitySpec {
+	// Вакансии малого города
+	smallJunior := IntRange{Min: 30000, Max: 45000}
+	smallSenior := IntRange{Min: 50000, Max: 75000}
+	smallPositions := IntRange{Min: 3, Max: 8}
+
+	// Вакансии большого города (более высокая стоимость жизни)
+	largeJunior := IntRange{Min: 35000, Max: 55000}
+	largeSenior := IntRange{Min: 60000, Max: 90000}
+	largePositions := IntRange{Min: 5, Max: 11}
+
+	// office создает рабочее место с младшей и старшей позицией.
+	// Некоторые старшие позиции требуют образования
+	office := func(capacity int, lat, lon float64, junior, senior, positions IntRange, diploma bool) BuildingSpec {
+		seniorVacancy := VacancySpec{Salary: senior, Positions: positions}
+		if diploma {
+			seniorVacancy.RequiredTags = []string{"engineer_diploma"}
+		}
+		return BuildingSpec{
+			Type:     "workplace",
+			Capacity: capacity,
+			Lat:      lat,
+			Lon:      lon,
+			Jobs: []JobSpec{{Vacancies: []VacancySpec{
+				{Salary: junior, Positions: positions},
+				seniorVacancy,
+			}}},
+		}
+	}
+
+	return []CitySpec{
+		{
+			// Малый город: 1 больница, 1 школа, 2 рабочих места, 1 развлечение, 1 кафе, 1 магазин, 3 жилых дома
+			Name:           "City 1",
+			Population:     40,
+			ApartmentPrice: 2000000, // 2 миллиона рублей
+			Center:         BuildingCoordinate{Lat: 55.563289, Lon: 39.427530},
+			Buildings: []BuildingSpec{
+				{Type: "hospital", Capacity: 50, Lat: 55.5681, Lon: 39.4260},
+				{Type: "school", Capacity: 200, Lat: 55.5656, Lon: 39.4241},
+				office(100, 55.5658, 39.4342, smallJunior, smallSenior, smallPositions, true),
+				office(100, 55.5664, 39.4254, smallJunior, smallSenior, smallPositions, false),
+				{Type: "entertainment", Capacity: 150, Lat: 55.5575, Lon: 39.4315},
+				{Type: "cafe", Capacity: 30, Lat: 55.5651, Lon: 39.4268},
+				{Type: "shop", Capacity: 40, Lat: 55.5685, Lon: 39.4185},
+				{Type: "residential_house", Capacity: 30, Lat: 55.5670, Lon: 39.4258},
+				{Type: "residential_house", Capacity: 30, Lat: 55.5670, Lon: 39.4258},
+				{Type: "residential_house", Capacity: 30, Lat: 55.5665, Lon: 39.4264},
+			},
+		},
+		{
+			// Большой город: 2 больницы, 2 школы, 3 рабочих места, 1 развлечение, 2 кафе, 2 магазина, 3 жилых дома
+			Name:           "City 2",
+			Population:     60,
+			ApartmentPrice: 3000000, // 3 миллиона рублей
+			Center:         BuildingCoordinate{Lat: 55.579629, Lon: 39.531722},
+			Buildings: []BuildingSpec{
+				{Type: "hospital", Capacity: 75, Lat: 55.581565, Lon: 39.541703},
+				{Type: "hospital", Capacity: 75, Lat: 55.581960, Lon: 39.540375},
+				{Type: "school", Capacity: 300, Lat: 55.581436, Lon: 39.533431},
+				{Type: "school", Capacity: 300, Lat: 55.577821, Lon: 39.527903},
+				office(150, 55.582656, 39.533124, largeJunior, largeSenior, largePositions, true),
+				office(150, 55.572849, 39.531288, largeJunior, largeSenior, largePositions, true),
+				office(150, 55.579003, 39.541344, largeJunior, largeSenior, largePositions, false),
+				{Type: "entertainment", Capacity: 200, Lat: 55.582575, Lon: 39.522617},
+				{Type: "cafe", Capacity: 40, Lat: 55.575140, Lon: 39.525415},
+				{Type: "cafe", Capacity: 40, Lat: 55.575915, Lon: 39.528772},
+				{Type: "shop", Capacity: 50, Lat: 55.576933, Lon: 39.521386},
+				{Type: "shop", Capacity: 50, Lat: 55.577918, Lon: 39.526411},
+				{Type: "residential_house", Capacity: 45, Lat: 55.578274, Lon: 39.521161},
+				{Type: "residential_house", Capacity: 45, Lat: 55.579445, Lon: 39.527625},
+				{Type: "residential_house", Capacity: 45, Lat: 55.579132, Lon: 39.524888},
+			},
+		},
+	}
+}
+
+// CitiesPopulation возвращает суммарное население городов
+func CitiesPopulation(cities []CitySpec) int {
+	total := 0
+	for _, city := range cities {
+		total += city.Population
+	}
+	return total
+}
@@ -1,55 +1,47 @@
 package config
 
+// Значения ниже - параметры сценария по умолчанию.
+// Их можно переопределить файлом сценария (см. scenario.go)
+
+// Файлы конфигурации целей, действий и всплесков
+var (
+	ActionsFile       = "actions.ini"
+	LocalTargetsFile  = "local.ini"
+	GlobalTargetsFile = "global.ini"
+	SplashesFile      = "splashes.ini"
+)
+
 // Константы населения и занятости
-const (
-	// Общее население в симуляции
-	TotalPopulation = 100
+var (
+	// Общее население в симуляции (сумма населения городов, см. cities.go)
+	TotalPopulation = CitiesPopulation(Cities)
 
 	// Уровень занятости (90% трудоустроены, 10% безработные - типично для России)
 	EmploymentRate = 0.9
-
-	// Распределение населения по городам
-	SmallCityPopulation = 0.4 * TotalPopulation // 40% в малом городе
-	LargeCityPopulation = 0.6 * TotalPopulation // 60% в большом городе
 )
 
 // Экономические константы
-const (
+var (
 	// Стартовый капитал для каждого человека
-	StartingMoney = 10000 // рубли
+	StartingMoney int64 = 10000 // рубли
 
-	// Ежедневные расходы на жизнь
+	// Ежедневные расходы на жизнь (их оплачивает бюджет домохозяйства)
 	DailyExpenses = 500 // рубли в день
 
-	// Диапазоны зарплат для малого города
-	SmallCityJuniorSalaryMin = 30000 // рубли/месяц
-	SmallCityJuniorSalaryMax = 45000 // рубли/месяц
-	SmallCitySeniorSalaryMin = 50000 // рубли/месяц
-	SmallCitySeniorSalaryMax = 75000 // рубли/месяц
-
-	// Диапазоны зарплат для большого города (более высокая стоимость жизни)
-	LargeCityJuniorSalaryMin = 35000 // рубли/месяц
-	LargeCityJuniorSalaryMax = 55000 // рубли/месяц
-	LargeCitySeniorSalaryMin = 60000 // рубли/месяц
-	LargeCitySeniorSalaryMax = 90000 // рубли/месяц
-
-	// Количество вакансий
-	SmallCityVacanciesMin = 3  // минимум позиций на вакансию
-	SmallCityVacanciesMax = 8  // максимум позиций на вакансию (3 + 5)
-	LargeCityVacanciesMin = 5  // минимум позиций на вакансию
-	LargeCityVacanciesMax = 11 // максимум позиций на вакансию (5 + 6)
+	// Доля зарплаты, которая идет в общий бюджет домохозяйства (остальное - личные деньги)
+	HouseholdContributionShare = 0.5
 )
 
 // Константы рынка труда
-const (
+var (
 	// Частота поиска работы для безработных (каждые 24 часа)
-	UnemployedJobSearchInterval = 24
+	UnemployedJobSearchInterval uint64 = 24
 
 	// Частота проверки рынка труда для трудоустроенных (каждую неделю)
-	EmployedJobSearchInterval = 168 // часов в неделе
+	EmployedJobSearchInterval uint64 = 168 // часов в неделе
 
 	// Минимальный опыт работы перед сменой работы
-	MinJobExperienceForSwitch = 168 // часов (1 неделя)
+	MinJobExperienceForSwitch uint64 = 168 // часов (1 неделя)
 
 	// Минимальное увеличение зарплаты для рассмотрения смены работы
 	MinSalaryIncreasePercent = 1.10 // 10% увеличение
@@ -63,27 +55,124 @@ const (
 	MaxJobChangeProbability = 0.6 // 60% максимальный шанс
 )
 
+// Константы миграции между городами
+var (
+	// Как часто люди обдумывают переезд (раз в сутки)
+	MigrationCheckInterval uint64 = 24
+
+	// Безработица дольше этого срока - повод искать работу в другом городе
+	LongTermUnemploymentHours uint64 = 720 // часов (30 дней)
+
+	// Во сколько раз зарплата в другом городе должна превышать текущую
+	MigrationSalaryGain = 1.3 // 30% увеличение
+
+	// Вероятность переезда при наличии подходящей работы (за одну проверку)
+	MigrationProbability = 0.2
+)
+
 // Константы возраста и жизни
-const (
+var (
 	// Параметры генерации возраста
 	MinAge    = 20.0
 	MaxAge    = 80.0
 	MeanAge   = 25.0
 	AgeStdDev = 10.0
 
-	// Порог возраста смерти
-	DeathAgeFemale = 78.0
-	DeathAgeMale = 68.0
-
 	// Диапазон опыта работы для первоначального назначения работы
 	MaxInitialWorkExperience = 2000 // часы
+
+	// Границы этапов жизни, от которых зависит набор глобальных целей
+	AdultAge      = 18.0
+	RetirementAge = 60.0
+)
+
+// Константы смертности.
+// Годовой риск смерти по модели Гомперца-Мейкхема: Accident + (Illness + OldAge)·e^(Growth·возраст).
+// Риск болезней и старости меняют модификаторы: прогресс глобальных целей, предметы и всплески
+var (
+	MaleMortality   = MortalityTable{Accident: 0.0008, Illness: 0.00006, OldAge: 0.0001, Growth: 0.09}
+	FemaleMortality = MortalityTable{Accident: 0.0003, Illness: 0.00003, OldAge: 0.00005, Growth: 0.09}
+
+	MortalityModifiers = []MortalityModifier{
+		{Target: "physical_perfection", Factor: 0.7},          // спорт и дисциплина
+		{Target: "healthy_old_age", Factor: 0.8},              // профилактика и отдых в старости
+		{SplashTag: "checkup", Cause: "illness", Factor: 0.6}, // недавнее медицинское обследование
+		{SplashTag: "stress", Factor: 1.2},                    // за каждую единицу силы стресса
+	}
+)
+
+// DeathCauses - причины смерти, на которые могут действовать модификаторы
+var DeathCauses = []string{"accident", "illness", "old_age"}
+
+// Константы здоровья
+var (
+	// Базовое здоровье (от 0 до 1) снижается на столько за каждый год старше AdultAge
+	HealthAgingDecay = 0.01
+
+	// На столько в год здоровье восстанавливается до базового, пока человек не болеет
+	HealthRecoveryRate = 0.5
+
+	// Потеря здоровья в год на единицу силы всплесков с тегом HealthStressTag
+	HealthStressDamage = 0.2
+	HealthStressTag    = "stress"
+
+	// Во сколько раз при нулевом здоровье чаще болезни и выше риск смерти от болезней
+	IllnessHealthFactor   = 3.0
+	MortalityHealthFactor = 3.0
+
+	// Больница: стоимость дня, ускорение выздоровления, снижение риска смерти от болезни
+	HospitalDayCost         int64  = 3000 // рубли
+	HospitalRecoverySpeed   uint64 = 2
+	HospitalMortalityFactor        = 0.5
+
+	// Сколько часов мать проводит в больнице после родов
+	MaternityStayHours uint64 = 72
+
+	// Больничные сверх этого числа рабочих часов за год работы - повод для увольнения
+	MaxSickLeaveHours uint64 = 240
+	SickLeaveFireRate        = 0.002 // 0.2% шанс в час
+
+	// Болезни: частота в год растет с возрастом как e^(AgeGrowth·возраст)
+	Illnesses = []IllnessSpec{
+		{Name: "cold", Rate: 1.5, DurationHours: 120, Severity: 0.02, Mortality: 1},
+		{Name: "flu", Rate: 0.3, DurationHours: 168, Severity: 0.05, Mortality: 5},
+		{Name: "pneumonia", Rate: 0.02, AgeGrowth: 0.02, DurationHours: 336, Severity: 0.15, Mortality: 50, Hospital: true},
+		{Name: "heart_attack", Rate: 0.00005, AgeGrowth: 0.08, DurationHours: 240, Severity: 0.3, Mortality: 1000, Hospital: true},
+	}
+)
+
+// Константы наследства
+var (
+	// Очереди наследников: наследство поровну делят живые наследники первой непустой очереди
+	HeirOrder = []string{"spouse", "children", "parents"}
+
+	// Переходят ли долги умершего к наследникам (иначе списываются)
+	InheritDebts = false
+
+	// Личные предметы, которые не передаются по наследству
+	NonTransferableItems = []string{"school_certificate", "engineer_diploma"}
+)
+
+// HeirGroups - допустимые очереди наследников
+var HeirGroups = []string{"spouse", "children", "parents"}
+
+// Константы детства и школы
+var (
+	// Возраст начала учебы в школе (дети учатся до AdultAge)
+	SchoolStartAge = 7.0
+
+	// Доля посещенных учебных часов, нужная для получения аттестата
+	MinSchoolAttendance = 0.8
+
+	// Предметы, которые выпускник получает с аттестатом
+	GraduationItems = []string{"school_certificate"}
 )
 
 // Константы увольнений и сокращений
-const (
+var (
 	// Плохая производительность (новые сотрудники)
-	NewEmployeePeriod       = 168  // часов (1 неделя)
-	PoorPerformanceFireRate = 0.01 // 1% шанс в час
+	NewEmployeePeriod       uint64 = 168  // часов (1 неделя)
+	PoorPerformanceFireRate        = 0.01 // 1% шанс в час
 
 	// Экономический спад
 	EconomicDownturnRate     = 0.0005 // 0.05% шанс в час
@@ -97,7 +186,7 @@ const (
 	BehavioralIssuesFireRate = 0.005 // 0.5% дополнительный шанс
 
 	// Возрастная дискриминация
-	AgeDiscriminationThreshold = 55     // лет
+	AgeDiscriminationThreshold = 55.0   // лет
 	AgeDiscriminationFireRate  = 0.0001 // 0.01% шанс в час
 
 	// Случайные увольнения
@@ -105,27 +194,6 @@ const (
 	RandomLayoffFireRate = 0.001   // 0.1% шанс
 )
 
-// Константы вместимости зданий
-const (
-	// Вместимость зданий малого города
-	SmallCityHospitalCapacity      = 50
-	SmallCitySchoolCapacity        = 200
-	SmallCityWorkplaceCapacity     = 100
-	SmallCityEntertainmentCapacity = 150
-	SmallCityCafeCapacity          = 30
-	SmallCityShopCapacity          = 40
-	SmallCityHouseCapacity         = 30
-
-	// Вместимость зданий большого города
-	LargeCityHospitalCapacity      = 75
-	LargeCitySchoolCapacity        = 300
-	LargeCityWorkplaceCapacity     = 150
-	LargeCityEntertainmentCapacity = 200
-	LargeCityCafeCapacity          = 40
-	LargeCityShopCapacity          = 50
-	LargeCityHouseCapacity         = 45
-)
-
 // Константы времени симуляции
 const (
 	// Единицы времени
@@ -134,59 +202,62 @@ const (
 	HoursPerMonth = 30 * HoursPerDay
 	HoursPerYear  = 365 * HoursPerDay
 
-	// Продолжительность симуляции
-	SimulationYears      = 2
-	TotalSimulationHours = 48
+	// Продолжительность симуляции в годах (справочно, фактическая длительность - TotalSimulationHours)
+	SimulationYears = 2
+)
+
+// Продолжительность симуляции
+var (
+	TotalSimulationHours uint64 = 48
 )
 
 // Распределение по полу
-const (
+var (
 	// Вероятность мужского пола (разделение 50/50)
 	MaleGenderProbability = 0.5
 )
 
 // Назначение глобальных целей
-const (
-	// Количество глобальных целей на человека
+var (
+	// Количество глобальных целей на человека: от MinGlobalTargets до MaxGlobalTargets (не включая)
 	MinGlobalTargets = 2
 	MaxGlobalTargets = 4 // 2 + 2
+
+	// Сколько часов цель может оставаться невыполнимой без продвижения, прежде чем человек от нее откажется
+	GoalPatienceHours uint64 = HoursPerYear / 2
 )
 
-// Константы всплесков (временных потребностей)
-const (
-	// Время жизни всплесков в часах
-	NeedMoneyLifetime         = 24
-	JobLossLifetime           = 72
-	CareerAdvancementLifetime = 48
+// Константы всплесков (временных потребностей, описаны в splashes.ini)
+var (
+	// Затухающий всплеск исчезает, когда его сила становится меньше этого значения
+	MinSplashIntensity = 0.1
 )
 
 // Константы расписания сна
-const (
+var (
 	// Часы сна (23:00 до 07:00)
-	SleepStartHour = 23 // 23:00
-	SleepEndHour   = 7  // 07:00
-	SleepDuration  = 8  // часов сна
+	SleepStartHour uint64 = 23 // 23:00
+	SleepEndHour   uint64 = 7  // 07:00
 )
 
 // Константы рабочего расписания
-const (
+var (
 	// Рабочие часы (09:00 до 18:00)
-	WorkStartHour = 9  // 09:00
-	WorkEndHour   = 18 // 18:00
-	WorkDuration  = 9  // часов работы
+	WorkStartHour uint64 = 9  // 09:00
+	WorkEndHour   uint64 = 18 // 18:00
 )
 
 // Константы семьи и рождения
-const (
+var (
 	// Брак и планирование семьи
 	MinMarriageDurationForChildren = 8760  // часов (1 год)
 	MinFamilyIncomeForChildren     = 50000 // рубли совокупный месячный доход
 	MaxChildrenPerFamily           = 4     // максимум детей на пару
 
 	// Вероятность рождения и время
-	BaseBirthPlanningProbability = 0.25 // 25% шанс в месяц для подходящих пар
-	PregnancyDurationHours       = 6480 // часов (9 месяцев)
-	ChildExpensesPerDay          = 300  // рубли на ребенка в день
+	BaseBirthPlanningProbability        = 0.25 // 25% шанс в месяц для подходящих пар
+	PregnancyDurationHours       uint64 = 6480 // часов (9 месяцев)
+	ChildExpensesPerDay          int64  = 300  // рубли на ребенка в день
 
 	// Возрастные ограничения для рождения детей
 	MinMotherAge = 18.0
@@ -195,8 +266,40 @@ const (
 	MaxFatherAge = 60.0
 
 	// Коэффициенты дружелюбности города к семьям
-	BaseFamilyCoefficient = 1.0  // базовый множитель
-	HospitalFamilyBonus   = 0.2  // +20% за больницу
-	SchoolFamilyBonus     = 0.15 // +15% за школу
-	MaxFamilyCoefficient  = 2.5  // максимальный множитель
+	BaseFamilyCoefficient    = 1.0 // базовый множитель
+	HospitalFamilyBonus      = 0.3 // +30% за больницу
+	SchoolFamilyBonus        = 0.4 // +40% за школу
+	EntertainmentFamilyBonus = 0.2 // +20% за развлекательный центр
+	CafeFamilyBonus          = 0.1 // +10% за кафе
+	ShopFamilyBonus          = 0.1 // +10% за магазин
+	MaxFamilyCoefficient     = 3.0 // максимальный множитель
+)
+
+// Константы разводов
+var (
+	// Базовая вероятность развода пары в час (около 9% в год)
+	BaseDivorceRate = 0.00001
+
+	// Надбавка за каждого супруга с денежным стрессом (нехватка денег, потеря работы)
+	MoneyStressDivorceRate = 0.00005
+
+	// Надбавка за каждого безработного супруга
+	UnemploymentDivorceRate = 0.00002
+
+	// Через столько лет брака риск развода снижается вдвое
+	DivorceStabilityYears = 5.0
 )
+
+// Константы принятия решений
+var (
+	// Планировщик действий: "targets" - через глобальные и локальные цели, "utility" - по полезности действий
+	PlannerType = "targets"
+
+	// Веса оценки полезности действия (планировщик utility)
+	UtilityProgressWeight = 1.0 // за закрытые теги цели с учетом ее силы
+	UtilityCostWeight     = 0.5 // штраф за цену относительно денег человека
+	UtilityTimeWeight     = 0.1 // штраф за время выполнения (логарифм часов)
+)
+
+// PlannerTypes - допустимые планировщики
+var PlannerTypes = []string{"targets", "utility"}
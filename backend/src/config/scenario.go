@@ -0,0 +1,847 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Scenario описывает все параметры эксперимента.
+// Файл сценария в формате JSON может задавать любое подмножество ключей,
+// отсутствующие ключи берутся из значений по умолчанию (constants.go)
+type Scenario struct {
+	Files      FilesSection      `json:"files"`
+	Simulation SimulationSection `json:"simulation"`
+	Population PopulationSection `json:"population"`
+	Economy    EconomySection    `json:"economy"`
+	JobMarket  JobMarketSection  `json:"job_market"`
+	Firing     FiringSection     `json:"firing"`
+	Migration  MigrationSection  `json:"migration"`
+	Life       LifeSection       `json:"life"`
+	School     SchoolSection     `json:"school"`
+	Splashes   SplashesSection   `json:"splashes"`
+	Schedule   ScheduleSection   `json:"schedule"`
+	Family     FamilySection     `json:"family"`
+	Divorce    DivorceSection    `json:"divorce"`
+	Planner    PlannerSection    `json:"planner"`
+	Mortality  MortalitySection  `json:"mortality"`
+	Health     HealthSection     `json:"health"`
+	Estate     EstateSection     `json:"estate"`
+	Cities     []CitySpec        `json:"cities"` // заменяет список городов по умолчанию целиком
+	Paths      []PathSpec        `json:"paths"`  // заменяет список дорог по умолчанию целиком
+}
+
+// IntRange - целочисленный диапазон [Min, Max)
+type IntRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// FloatRange - вещественный диапазон [Min, Max]
+type FloatRange struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// FilesSection - пути к файлам действий и целей (относительно файла сценария)
+type FilesSection struct {
+	Actions       string `json:"actions"`
+	LocalTargets  string `json:"local_targets"`
+	GlobalTargets string `json:"global_targets"`
+	Splashes      string `json:"splashes"`
+}
+
+type SimulationSection struct {
+	Hours uint64 `json:"hours"`
+}
+
+type PopulationSection struct {
+	EmploymentRate    float64 `json:"employment_rate"`
+	MaleProbability   float64 `json:"male_probability"`
+	MinAge            float64 `json:"min_age"`
+	MaxAge            float64 `json:"max_age"`
+	MeanAge           float64 `json:"mean_age"`
+	AgeStdDev         float64 `json:"age_std_dev"`
+	MinGlobalTargets  int     `json:"min_global_targets"`
+	MaxGlobalTargets  int     `json:"max_global_targets"`
+	GoalPatienceHours uint64  `json:"goal_patience_hours"`
+}
+
+type EconomySection struct {
+	StartingMoney         int64   `json:"starting_money"`
+	DailyExpenses         int     `json:"daily_expenses"`
+	HouseholdContribution float64 `json:"household_contribution"`
+}
+
+type JobMarketSection struct {
+	UnemployedSearchInterval uint64     `json:"unemployed_search_interval"`
+	EmployedSearchInterval   uint64     `json:"employed_search_interval"`
+	MinExperienceForSwitch   uint64     `json:"min_experience_for_switch"`
+	MinSalaryIncrease        float64    `json:"min_salary_increase"`
+	MinSkillMatchForJob      float64    `json:"min_skill_match_for_job"`
+	MinSkillMatchForSwitch   float64    `json:"min_skill_match_for_switch"`
+	ChangeProbability        FloatRange `json:"change_probability"`
+}
+
+type FiringSection struct {
+	NewEmployeePeriod          uint64  `json:"new_employee_period"`
+	PoorPerformanceRate        float64 `json:"poor_performance_rate"`
+	EconomicDownturnRate       float64 `json:"economic_downturn_rate"`
+	EconomicDownturnFireRate   float64 `json:"economic_downturn_fire_rate"`
+	HighSalaryThreshold        int     `json:"high_salary_threshold"`
+	RestructuringRate          float64 `json:"restructuring_rate"`
+	BehavioralIssuesRate       float64 `json:"behavioral_issues_rate"`
+	AgeDiscriminationThreshold float64 `json:"age_discrimination_threshold"`
+	AgeDiscriminationRate      float64 `json:"age_discrimination_rate"`
+	RandomLayoffBaseRate       float64 `json:"random_layoff_base_rate"`
+	RandomLayoffRate           float64 `json:"random_layoff_rate"`
+}
+
+type MigrationSection struct {
+	CheckInterval             uint64  `json:"check_interval"`
+	LongTermUnemploymentHours uint64  `json:"long_term_unemployment_hours"`
+	SalaryGain                float64 `json:"salary_gain"`
+	Probability               float64 `json:"probability"`
+}
+
+type LifeSection struct {
+	MaxInitialWorkExperience int     `json:"max_initial_work_experience"`
+	AdultAge                 float64 `json:"adult_age"`
+	RetirementAge            float64 `json:"retirement_age"`
+}
+
+type SchoolSection struct {
+	StartAge        float64  `json:"start_age"`
+	MinAttendance   float64  `json:"min_attendance"`
+	GraduationItems []string `json:"graduation_items"`
+}
+
+// SplashesSection - параметры всплесков (сами всплески описаны в files.splashes)
+type SplashesSection struct {
+	MinIntensity float64 `json:"min_intensity"`
+}
+
+type ScheduleSection struct {
+	SleepStartHour uint64 `json:"sleep_start_hour"`
+	SleepEndHour   uint64 `json:"sleep_end_hour"`
+	WorkStartHour  uint64 `json:"work_start_hour"`
+	WorkEndHour    uint64 `json:"work_end_hour"`
+}
+
+type FamilySection struct {
+	MinMarriageDurationForChildren int        `json:"min_marriage_duration_for_children"`
+	MinFamilyIncomeForChildren     int        `json:"min_family_income_for_children"`
+	MaxChildrenPerFamily           int        `json:"max_children_per_family"`
+	BirthPlanningProbability       float64    `json:"birth_planning_probability"`
+	PregnancyDurationHours         uint64     `json:"pregnancy_duration_hours"`
+	ChildExpensesPerDay            int64      `json:"child_expenses_per_day"`
+	MotherAge                      FloatRange `json:"mother_age"`
+	FatherAge                      FloatRange `json:"father_age"`
+	BaseCoefficient                float64    `json:"base_coefficient"`
+	HospitalBonus                  float64    `json:"hospital_bonus"`
+	SchoolBonus                    float64    `json:"school_bonus"`
+	EntertainmentBonus             float64    `json:"entertainment_bonus"`
+	CafeBonus                      float64    `json:"cafe_bonus"`
+	ShopBonus                      float64    `json:"shop_bonus"`
+	MaxCoefficient                 float64    `json:"max_coefficient"`
+}
+
+type DivorceSection struct {
+	BaseRate         float64 `json:"base_rate"`
+	MoneyStressRate  float64 `json:"money_stress_rate"`
+	UnemploymentRate float64 `json:"unemployment_rate"`
+	StabilityYears   float64 `json:"stability_years"`
+}
+
+type PlannerSection struct {
+	Type           string  `json:"type"`
+	ProgressWeight float64 `json:"progress_weight"`
+	CostWeight     float64 `json:"cost_weight"`
+	TimeWeight     float64 `json:"time_weight"`
+}
+
+// MortalitySection - таблицы смертности по полу и модификаторы риска
+type MortalitySection struct {
+	Male      MortalityTable      `json:"male"`
+	Female    MortalityTable      `json:"female"`
+	Modifiers []MortalityModifier `json:"modifiers"` // заменяет список по умолчанию целиком
+}
+
+// MortalityTable - годовой риск смерти для одного пола: Accident + (Illness + OldAge)·e^(Growth·возраст)
+type MortalityTable struct {
+	Accident float64 `json:"accident"` // несчастные случаи, не зависят от возраста (слагаемое Мейкхема)
+	Illness  float64 `json:"illness"`
+	OldAge   float64 `json:"old_age"`
+	Growth   float64 `json:"growth"` // скорость роста риска с возрастом (закон Гомперца)
+}
+
+// MortalityModifier умножает риск смерти на Factor. Задается ровно одно из условий:
+// Target - глобальная цель (действует пропорционально ее прогрессу, полностью - после выполнения),
+// Item - предмет (действует, пока он есть), SplashTag - тег всплесков (Factor в степени их силы)
+type MortalityModifier struct {
+	Target    string  `json:"target,omitempty"`
+	Item      string  `json:"item,omitempty"`
+	SplashTag string  `json:"splash_tag,omitempty"`
+	Cause     string  `json:"cause,omitempty"` // пусто - болезни и старость
+	Factor    float64 `json:"factor"`
+}
+
+type HealthSection struct {
+	AgingDecay              float64       `json:"aging_decay"`
+	RecoveryRate            float64       `json:"recovery_rate"`
+	StressDamage            float64       `json:"stress_damage"`
+	StressTag               string        `json:"stress_tag"`
+	IllnessHealthFactor     float64       `json:"illness_health_factor"`
+	MortalityHealthFactor   float64       `json:"mortality_health_factor"`
+	HospitalDayCost         int64         `json:"hospital_day_cost"`
+	HospitalRecoverySpeed   uint64        `json:"hospital_recovery_speed"`
+	HospitalMortalityFactor float64       `json:"hospital_mortality_factor"`
+	MaternityStayHours      uint64        `json:"maternity_stay_hours"`
+	MaxSickLeaveHours       uint64        `json:"max_sick_leave_hours"`
+	SickLeaveFireRate       float64       `json:"sick_leave_fire_rate"`
+	Illnesses               []IllnessSpec `json:"illnesses"` // заменяет список по умолчанию целиком
+}
+
+// IllnessSpec описывает болезнь
+type IllnessSpec struct {
+	Name          string  `json:"name"`
+	Rate          float64 `json:"rate"`       // заболеваний в год (при полном здоровье, без учета возраста)
+	AgeGrowth     float64 `json:"age_growth"` // рост частоты с возрастом
+	DurationHours uint64  `json:"duration_hours"`
+	Severity      float64 `json:"severity"`  // сколько здоровья отнимает
+	Mortality     float64 `json:"mortality"` // множитель риска смерти от болезней, пока человек болеет
+	Hospital      bool    `json:"hospital"`  // лечится в больнице
+}
+
+// EstateSection - правила наследования
+type EstateSection struct {
+	HeirOrder            []string `json:"heir_order"` // заменяет список по умолчанию целиком
+	InheritDebts         bool     `json:"inherit_debts"`
+	NonTransferableItems []string `json:"non_transferable_items"` // заменяет список по умолчанию целиком
+}
+
+// DefaultScenario возвращает сценарий с текущими значениями параметров
+func DefaultScenario() *Scenario {
+	return &Scenario{
+		Files: FilesSection{
+			Actions:       ActionsFile,
+			LocalTargets:  LocalTargetsFile,
+			GlobalTargets: GlobalTargetsFile,
+			Splashes:      SplashesFile,
+		},
+		Simulation: SimulationSection{
+			Hours: TotalSimulationHours,
+		},
+		Population: PopulationSection{
+			EmploymentRate:    EmploymentRate,
+			MaleProbability:   MaleGenderProbability,
+			MinAge:            MinAge,
+			MaxAge:            MaxAge,
+			MeanAge:           MeanAge,
+			AgeStdDev:         AgeStdDev,
+			MinGlobalTargets:  MinGlobalTargets,
+			MaxGlobalTargets:  MaxGlobalTargets,
+			GoalPatienceHours: GoalPatienceHours,
+		},
+		Economy: EconomySection{
+			StartingMoney:         StartingMoney,
+			DailyExpenses:         DailyExpenses,
+			HouseholdContribution: HouseholdContributionShare,
+		},
+		JobMarket: JobMarketSection{
+			UnemployedSearchInterval: UnemployedJobSearchInterval,
+			EmployedSearchInterval:   EmployedJobSearchInterval,
+			MinExperienceForSwitch:   MinJobExperienceForSwitch,
+			MinSalaryIncrease:        MinSalaryIncreasePercent,
+			MinSkillMatchForJob:      MinSkillMatchForJob,
+			MinSkillMatchForSwitch:   MinSkillMatchForSwitch,
+			ChangeProbability:        FloatRange{Min: MinJobChangeProbability, Max: MaxJobChangeProbability},
+		},
+		Firing: FiringSection{
+			NewEmployeePeriod:          NewEmployeePeriod,
+			PoorPerformanceRate:        PoorPerformanceFireRate,
+			EconomicDownturnRate:       EconomicDownturnRate,
+			EconomicDownturnFireRate:   EconomicDownturnFireRate,
+			HighSalaryThreshold:        HighSalaryThreshold,
+			RestructuringRate:          RestructuringFireRate,
+			BehavioralIssuesRate:       BehavioralIssuesFireRate,
+			AgeDiscriminationThreshold: AgeDiscriminationThreshold,
+			AgeDiscriminationRate:      AgeDiscriminationFireRate,
+			RandomLayoffBaseRate:       RandomLayoffBaseRate,
+			RandomLayoffRate:           RandomLayoffFireRate,
+		},
+		Migration: MigrationSection{
+			CheckInterval:             MigrationCheckInterval,
+			LongTermUnemploymentHours: LongTermUnemploymentHours,
+			SalaryGain:                MigrationSalaryGain,
+			Probability:               MigrationProbability,
+		},
+		Life: LifeSection{
+			MaxInitialWorkExperience: MaxInitialWorkExperience,
+			AdultAge:                 AdultAge,
+			RetirementAge:            RetirementAge,
+		},
+		School: SchoolSection{
+			StartAge:        SchoolStartAge,
+			MinAttendance:   MinSchoolAttendance,
+			GraduationItems: GraduationItems,
+		},
+		Splashes: SplashesSection{
+			MinIntensity: MinSplashIntensity,
+		},
+		Schedule: ScheduleSection{
+			SleepStartHour: SleepStartHour,
+			SleepEndHour:   SleepEndHour,
+			WorkStartHour:  WorkStartHour,
+			WorkEndHour:    WorkEndHour,
+		},
+		Family: FamilySection{
+			MinMarriageDurationForChildren: MinMarriageDurationForChildren,
+			MinFamilyIncomeForChildren:     MinFamilyIncomeForChildren,
+			MaxChildrenPerFamily:           MaxChildrenPerFamily,
+			BirthPlanningProbability:       BaseBirthPlanningProbability,
+			PregnancyDurationHours:         PregnancyDurationHours,
+			ChildExpensesPerDay:            ChildExpensesPerDay,
+			MotherAge:                      FloatRange{Min: MinMotherAge, Max: MaxMotherAge},
+			FatherAge:                      FloatRange{Min: MinFatherAge, Max: MaxFatherAge},
+			BaseCoefficient:                BaseFamilyCoefficient,
+			HospitalBonus:                  HospitalFamilyBonus,
+			SchoolBonus:                    SchoolFamilyBonus,
+			EntertainmentBonus:             EntertainmentFamilyBonus,
+			CafeBonus:                      CafeFamilyBonus,
+			ShopBonus:                      ShopFamilyBonus,
+			MaxCoefficient:                 MaxFamilyCoefficient,
+		},
+		Divorce: DivorceSection{
+			BaseRate:         BaseDivorceRate,
+			MoneyStressRate:  MoneyStressDivorceRate,
+			UnemploymentRate: UnemploymentDivorceRate,
+			StabilityYears:   DivorceStabilityYears,
+		},
+		Planner: PlannerSection{
+			Type:           PlannerType,
+			ProgressWeight: UtilityProgressWeight,
+			CostWeight:     UtilityCostWeight,
+			TimeWeight:     UtilityTimeWeight,
+		},
+		Mortality: MortalitySection{
+			Male:      MaleMortality,
+			Female:    FemaleMortality,
+			Modifiers: append([]MortalityModifier(nil), MortalityModifiers...),
+		},
+		Health: HealthSection{
+			AgingDecay:              HealthAgingDecay,
+			RecoveryRate:            HealthRecoveryRate,
+			StressDamage:            HealthStressDamage,
+			StressTag:               HealthStressTag,
+			IllnessHealthFactor:     IllnessHealthFactor,
+			MortalityHealthFactor:   MortalityHealthFactor,
+			HospitalDayCost:         HospitalDayCost,
+			HospitalRecoverySpeed:   HospitalRecoverySpeed,
+			HospitalMortalityFactor: HospitalMortalityFactor,
+			MaternityStayHours:      MaternityStayHours,
+			MaxSickLeaveHours:       MaxSickLeaveHours,
+			SickLeaveFireRate:       SickLeaveFireRate,
+			Illnesses:               append([]IllnessSpec(nil), Illnesses...),
+		},
+		Estate: EstateSection{
+			HeirOrder:            append([]string(nil), HeirOrder...),
+			InheritDebts:         InheritDebts,
+			NonTransferableItems: append([]string(nil), NonTransferableItems...),
+		},
+		Cities: Cities,
+		Paths:  Paths,
+	}
+}
+
+// LoadScenario читает файл сценария поверх значений по умолчанию и проверяет его.
+// Пути к ini файлам разрешаются относительно каталога файла сценария
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario %s: %v", path, err)
+	}
+
+	scenario := DefaultScenario()
+
+	// Списки городов и дорог в файле заменяют значения по умолчанию, а не дополняют их.
+	// Дороги по умолчанию ссылаются на города по умолчанию, поэтому сбрасываются вместе с ними
+	var keys map[string]json.RawMessage
+	if json.Unmarshal(data, &keys) == nil {
+		if _, exists := keys["cities"]; exists {
+			scenario.Cities = nil
+			scenario.Paths = nil
+		}
+		if _, exists := keys["paths"]; exists {
+			scenario.Paths = nil
+		}
+
+		// Иначе элементы списков из файла разбирались бы поверх элементов по умолчанию
+		if hasNestedKey(keys["mortality"], "modifiers") {
+			scenario.Mortality.Modifiers = nil
+		}
+		if hasNestedKey(keys["health"], "illnesses") {
+			scenario.Health.Illnesses = nil
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(scenario); err != nil {
+		return nil, fmt.Errorf("scenario %s: %v", path, describeDecodeError(data, err))
+	}
+
+	if err := scenario.Validate(); err != nil {
+		return nil, fmt.Errorf("scenario %s: %v", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	scenario.Files.Actions = resolvePath(dir, scenario.Files.Actions)
+	scenario.Files.LocalTargets = resolvePath(dir, scenario.Files.LocalTargets)
+	scenario.Files.GlobalTargets = resolvePath(dir, scenario.Files.GlobalTargets)
+	scenario.Files.Splashes = resolvePath(dir, scenario.Files.Splashes)
+
+	return scenario, nil
+}
+
+// Validate проверяет значения сценария; ошибка содержит полное имя ключа
+func (s *Scenario) Validate() error {
+	v := &validator{}
+
+	v.notEmpty("files.actions", s.Files.Actions)
+	v.notEmpty("files.local_targets", s.Files.LocalTargets)
+	v.notEmpty("files.global_targets", s.Files.GlobalTargets)
+	v.notEmpty("files.splashes", s.Files.Splashes)
+
+	v.positive("simulation.hours", float64(s.Simulation.Hours))
+
+	v.probability("population.employment_rate", s.Population.EmploymentRate)
+	v.probability("population.male_probability", s.Population.MaleProbability)
+	v.nonNegative("population.min_age", s.Population.MinAge)
+	v.check(s.Population.MinAge <= s.Population.MaxAge, "population.max_age",
+		"must not be less than population.min_age (%g)", s.Population.MinAge)
+	v.nonNegative("population.age_std_dev", s.Population.AgeStdDev)
+	v.positive("population.min_global_targets", float64(s.Population.MinGlobalTargets))
+	v.check(s.Population.MinGlobalTargets < s.Population.MaxGlobalTargets, "population.max_global_targets",
+		"must be greater than population.min_global_targets (%d)", s.Population.MinGlobalTargets)
+	v.positive("population.goal_patience_hours", float64(s.Population.GoalPatienceHours))
+
+	v.nonNegative("economy.starting_money", float64(s.Economy.StartingMoney))
+	v.nonNegative("economy.daily_expenses", float64(s.Economy.DailyExpenses))
+	v.probability("economy.household_contribution", s.Economy.HouseholdContribution)
+
+	v.positive("job_market.unemployed_search_interval", float64(s.JobMarket.UnemployedSearchInterval))
+	v.positive("job_market.employed_search_interval", float64(s.JobMarket.EmployedSearchInterval))
+	v.check(s.JobMarket.MinSalaryIncrease >= 1, "job_market.min_salary_increase", "must be at least 1.0")
+	v.probability("job_market.min_skill_match_for_job", s.JobMarket.MinSkillMatchForJob)
+	v.probability("job_market.min_skill_match_for_switch", s.JobMarket.MinSkillMatchForSwitch)
+	v.probability("job_market.change_probability.min", s.JobMarket.ChangeProbability.Min)
+	v.probability("job_market.change_probability.max", s.JobMarket.ChangeProbability.Max)
+	v.floatRange("job_market.change_probability", s.JobMarket.ChangeProbability)
+
+	v.probability("firing.poor_performance_rate", s.Firing.PoorPerformanceRate)
+	v.probability("firing.economic_downturn_rate", s.Firing.EconomicDownturnRate)
+	v.probability("firing.economic_downturn_fire_rate", s.Firing.EconomicDownturnFireRate)
+	v.nonNegative("firing.high_salary_threshold", float64(s.Firing.HighSalaryThreshold))
+	v.probability("firing.restructuring_rate", s.Firing.RestructuringRate)
+	v.probability("firing.behavioral_issues_rate", s.Firing.BehavioralIssuesRate)
+	v.nonNegative("firing.age_discrimination_threshold", s.Firing.AgeDiscriminationThreshold)
+	v.probability("firing.age_discrimination_rate", s.Firing.AgeDiscriminationRate)
+	v.probability("firing.random_layoff_base_rate", s.Firing.RandomLayoffBaseRate)
+	v.probability("firing.random_layoff_rate", s.Firing.RandomLayoffRate)
+
+	v.positive("migration.check_interval", float64(s.Migration.CheckInterval))
+	v.check(s.Migration.SalaryGain >= 1, "migration.salary_gain", "must be at least 1.0")
+	v.probability("migration.probability", s.Migration.Probability)
+
+	v.positive("life.max_initial_work_experience", float64(s.Life.MaxInitialWorkExperience))
+	v.positive("life.adult_age", s.Life.AdultAge)
+	v.check(s.Life.AdultAge < s.Life.RetirementAge, "life.retirement_age",
+		"must be greater than life.adult_age (%g)", s.Life.AdultAge)
+
+	v.nonNegative("school.start_age", s.School.StartAge)
+	v.check(s.School.StartAge < s.Life.AdultAge, "school.start_age",
+		"must be less than life.adult_age (%g)", s.Life.AdultAge)
+	v.probability("school.min_attendance", s.School.MinAttendance)
+	for i, item := range s.School.GraduationItems {
+		v.notEmpty(fmt.Sprintf("school.graduation_items[%d]", i), item)
+	}
+
+	v.probability("splashes.min_intensity", s.Splashes.MinIntensity)
+
+	v.hour("schedule.sleep_start_hour", s.Schedule.SleepStartHour)
+	v.hour("schedule.sleep_end_hour", s.Schedule.SleepEndHour)
+	v.hour("schedule.work_start_hour", s.Schedule.WorkStartHour)
+	v.hour("schedule.work_end_hour", s.Schedule.WorkEndHour)
+	v.check(s.Schedule.WorkStartHour < s.Schedule.WorkEndHour, "schedule.work_end_hour",
+		"must be after schedule.work_start_hour (%d)", s.Schedule.WorkStartHour)
+
+	v.nonNegative("family.min_marriage_duration_for_children", float64(s.Family.MinMarriageDurationForChildren))
+	v.nonNegative("family.min_family_income_for_children", float64(s.Family.MinFamilyIncomeForChildren))
+	v.nonNegative("family.max_children_per_family", float64(s.Family.MaxChildrenPerFamily))
+	v.probability("family.birth_planning_probability", s.Family.BirthPlanningProbability)
+	v.positive("family.pregnancy_duration_hours", float64(s.Family.PregnancyDurationHours))
+	v.nonNegative("family.child_expenses_per_day", float64(s.Family.ChildExpensesPerDay))
+	v.floatRange("family.mother_age", s.Family.MotherAge)
+	v.floatRange("family.father_age", s.Family.FatherAge)
+	v.positive("family.base_coefficient", s.Family.BaseCoefficient)
+	v.nonNegative("family.hospital_bonus", s.Family.HospitalBonus)
+	v.nonNegative("family.school_bonus", s.Family.SchoolBonus)
+	v.nonNegative("family.entertainment_bonus", s.Family.EntertainmentBonus)
+	v.nonNegative("family.cafe_bonus", s.Family.CafeBonus)
+	v.nonNegative("family.shop_bonus", s.Family.ShopBonus)
+	v.check(s.Family.MaxCoefficient >= s.Family.BaseCoefficient, "family.max_coefficient",
+		"must not be less than family.base_coefficient (%g)", s.Family.BaseCoefficient)
+
+	v.probability("divorce.base_rate", s.Divorce.BaseRate)
+	v.probability("divorce.money_stress_rate", s.Divorce.MoneyStressRate)
+	v.probability("divorce.unemployment_rate", s.Divorce.UnemploymentRate)
+	v.positive("divorce.stability_years", s.Divorce.StabilityYears)
+
+	v.check(isPlannerType(s.Planner.Type), "planner.type", "unknown planner %q (expected one of %v)", s.Planner.Type, PlannerTypes)
+	v.positive("planner.progress_weight", s.Planner.ProgressWeight)
+	v.nonNegative("planner.cost_weight", s.Planner.CostWeight)
+	v.nonNegative("planner.time_weight", s.Planner.TimeWeight)
+
+	v.mortalityTable("mortality.male", s.Mortality.Male)
+	v.mortalityTable("mortality.female", s.Mortality.Female)
+	for i, modifier := range s.Mortality.Modifiers {
+		v.mortalityModifier(fmt.Sprintf("mortality.modifiers[%d]", i), modifier)
+	}
+
+	v.nonNegative("health.aging_decay", s.Health.AgingDecay)
+	v.nonNegative("health.recovery_rate", s.Health.RecoveryRate)
+	v.nonNegative("health.stress_damage", s.Health.StressDamage)
+	v.check(s.Health.IllnessHealthFactor >= 1, "health.illness_health_factor", "must be at least 1.0")
+	v.check(s.Health.MortalityHealthFactor >= 1, "health.mortality_health_factor", "must be at least 1.0")
+	v.nonNegative("health.hospital_day_cost", float64(s.Health.HospitalDayCost))
+	v.positive("health.hospital_recovery_speed", float64(s.Health.HospitalRecoverySpeed))
+	v.positive("health.hospital_mortality_factor", s.Health.HospitalMortalityFactor)
+	v.positive("health.maternity_stay_hours", float64(s.Health.MaternityStayHours))
+	v.probability("health.sick_leave_fire_rate", s.Health.SickLeaveFireRate)
+	illnesses := make(map[string]bool)
+	for i, illness := range s.Health.Illnesses {
+		key := fmt.Sprintf("health.illnesses[%d]", i)
+		v.check(!illnesses[illness.Name], key+".name", "duplicate illness name %q", illness.Name)
+		illnesses[illness.Name] = true
+		v.illness(key, illness)
+	}
+
+	heirs := make(map[string]bool)
+	for i, group := range s.Estate.HeirOrder {
+		key := fmt.Sprintf("estate.heir_order[%d]", i)
+		v.check(isHeirGroup(group), key, "unknown heir group %q (expected one of %v)", group, HeirGroups)
+		v.check(!heirs[group], key, "duplicate heir group %q", group)
+		heirs[group] = true
+	}
+	for i, item := range s.Estate.NonTransferableItems {
+		v.notEmpty(fmt.Sprintf("estate.non_transferable_items[%d]", i), item)
+	}
+
+	v.check(len(s.Cities) > 0, "cities", "must contain at least one city")
+	names := make(map[string]bool)
+	for i, city := range s.Cities {
+		key := fmt.Sprintf("cities[%d]", i)
+		v.check(!names[city.Name], key+".name", "duplicate city name %q", city.Name)
+		names[city.Name] = true
+		v.city(key, city)
+	}
+
+	for i, path := range s.Paths {
+		key := fmt.Sprintf("paths[%d]", i)
+		v.check(names[path.From], key+".from", "unknown city %q", path.From)
+		v.check(names[path.To], key+".to", "unknown city %q", path.To)
+		v.check(path.From != path.To, key+".to", "must differ from %s.from", key)
+	}
+
+	return v.err
+}
+
+// Apply делает сценарий текущим: записывает его значения в параметры пакета
+func (s *Scenario) Apply() {
+	ActionsFile = s.Files.Actions
+	LocalTargetsFile = s.Files.LocalTargets
+	GlobalTargetsFile = s.Files.GlobalTargets
+	SplashesFile = s.Files.Splashes
+
+	TotalSimulationHours = s.Simulation.Hours
+
+	Cities = s.Cities
+	Paths = s.Paths
+	TotalPopulation = CitiesPopulation(Cities)
+	EmploymentRate = s.Population.EmploymentRate
+	MaleGenderProbability = s.Population.MaleProbability
+	MinAge = s.Population.MinAge
+	MaxAge = s.Population.MaxAge
+	MeanAge = s.Population.MeanAge
+	AgeStdDev = s.Population.AgeStdDev
+	MinGlobalTargets = s.Population.MinGlobalTargets
+	MaxGlobalTargets = s.Population.MaxGlobalTargets
+	GoalPatienceHours = s.Population.GoalPatienceHours
+
+	StartingMoney = s.Economy.StartingMoney
+	DailyExpenses = s.Economy.DailyExpenses
+	HouseholdContributionShare = s.Economy.HouseholdContribution
+
+	UnemployedJobSearchInterval = s.JobMarket.UnemployedSearchInterval
+	EmployedJobSearchInterval = s.JobMarket.EmployedSearchInterval
+	MinJobExperienceForSwitch = s.JobMarket.MinExperienceForSwitch
+	MinSalaryIncreasePercent = s.JobMarket.MinSalaryIncrease
+	MinSkillMatchForJob = s.JobMarket.MinSkillMatchForJob
+	MinSkillMatchForSwitch = s.JobMarket.MinSkillMatchForSwitch
+	MinJobChangeProbability = s.JobMarket.ChangeProbability.Min
+	MaxJobChangeProbability = s.JobMarket.ChangeProbability.Max
+
+	NewEmployeePeriod = s.Firing.NewEmployeePeriod
+	PoorPerformanceFireRate = s.Firing.PoorPerformanceRate
+	EconomicDownturnRate = s.Firing.EconomicDownturnRate
+	EconomicDownturnFireRate = s.Firing.EconomicDownturnFireRate
+	HighSalaryThreshold = s.Firing.HighSalaryThreshold
+	RestructuringFireRate = s.Firing.RestructuringRate
+	BehavioralIssuesFireRate = s.Firing.BehavioralIssuesRate
+	AgeDiscriminationThreshold = s.Firing.AgeDiscriminationThreshold
+	AgeDiscriminationFireRate = s.Firing.AgeDiscriminationRate
+	RandomLayoffBaseRate = s.Firing.RandomLayoffBaseRate
+	RandomLayoffFireRate = s.Firing.RandomLayoffRate
+
+	MigrationCheckInterval = s.Migration.CheckInterval
+	LongTermUnemploymentHours = s.Migration.LongTermUnemploymentHours
+	MigrationSalaryGain = s.Migration.SalaryGain
+	MigrationProbability = s.Migration.Probability
+
+	MaxInitialWorkExperience = s.Life.MaxInitialWorkExperience
+	AdultAge = s.Life.AdultAge
+	RetirementAge = s.Life.RetirementAge
+
+	SchoolStartAge = s.School.StartAge
+	MinSchoolAttendance = s.School.MinAttendance
+	GraduationItems = s.School.GraduationItems
+
+	MinSplashIntensity = s.Splashes.MinIntensity
+
+	SleepStartHour = s.Schedule.SleepStartHour
+	SleepEndHour = s.Schedule.SleepEndHour
+	WorkStartHour = s.Schedule.WorkStartHour
+	WorkEndHour = s.Schedule.WorkEndHour
+
+	MinMarriageDurationForChildren = s.Family.MinMarriageDurationForChildren
+	MinFamilyIncomeForChildren = s.Family.MinFamilyIncomeForChildren
+	MaxChildrenPerFamily = s.Family.MaxChildrenPerFamily
+	BaseBirthPlanningProbability = s.Family.BirthPlanningProbability
+	PregnancyDurationHours = s.Family.PregnancyDurationHours
+	ChildExpensesPerDay = s.Family.ChildExpensesPerDay
+	MinMotherAge = s.Family.MotherAge.Min
+	MaxMotherAge = s.Family.MotherAge.Max
+	MinFatherAge = s.Family.FatherAge.Min
+	MaxFatherAge = s.Family.FatherAge.Max
+	BaseFamilyCoefficient = s.Family.BaseCoefficient
+	HospitalFamilyBonus = s.Family.HospitalBonus
+	SchoolFamilyBonus = s.Family.SchoolBonus
+	EntertainmentFamilyBonus = s.Family.EntertainmentBonus
+	CafeFamilyBonus = s.Family.CafeBonus
+	ShopFamilyBonus = s.Family.ShopBonus
+	MaxFamilyCoefficient = s.Family.MaxCoefficient
+
+	BaseDivorceRate = s.Divorce.BaseRate
+	MoneyStressDivorceRate = s.Divorce.MoneyStressRate
+	UnemploymentDivorceRate = s.Divorce.UnemploymentRate
+	DivorceStabilityYears = s.Divorce.StabilityYears
+
+	PlannerType = s.Planner.Type
+	UtilityProgressWeight = s.Planner.ProgressWeight
+	UtilityCostWeight = s.Planner.CostWeight
+	UtilityTimeWeight = s.Planner.TimeWeight
+
+	MaleMortality = s.Mortality.Male
+	FemaleMortality = s.Mortality.Female
+	MortalityModifiers = s.Mortality.Modifiers
+
+	HealthAgingDecay = s.Health.AgingDecay
+	HealthRecoveryRate = s.Health.RecoveryRate
+	HealthStressDamage = s.Health.StressDamage
+	HealthStressTag = s.Health.StressTag
+	IllnessHealthFactor = s.Health.IllnessHealthFactor
+	MortalityHealthFactor = s.Health.MortalityHealthFactor
+	HospitalDayCost = s.Health.HospitalDayCost
+	HospitalRecoverySpeed = s.Health.HospitalRecoverySpeed
+	HospitalMortalityFactor = s.Health.HospitalMortalityFactor
+	MaternityStayHours = s.Health.MaternityStayHours
+	MaxSickLeaveHours = s.Health.MaxSickLeaveHours
+	SickLeaveFireRate = s.Health.SickLeaveFireRate
+	Illnesses = s.Health.Illnesses
+
+	HeirOrder = s.Estate.HeirOrder
+	InheritDebts = s.Estate.InheritDebts
+	NonTransferableItems = s.Estate.NonTransferableItems
+}
+
+// validator накапливает первую ошибку проверки сценария
+type validator struct {
+	err error
+}
+
+func (v *validator) check(ok bool, key, format string, args ...interface{}) {
+	if v.err == nil && !ok {
+		v.err = fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...))
+	}
+}
+
+func (v *validator) notEmpty(key, value string) {
+	v.check(value != "", key, "must not be empty")
+}
+
+func (v *validator) positive(key string, value float64) {
+	v.check(value > 0, key, "must be positive, got %g", value)
+}
+
+func (v *validator) nonNegative(key string, value float64) {
+	v.check(value >= 0, key, "must not be negative, got %g", value)
+}
+
+func (v *validator) probability(key string, value float64) {
+	v.check(value >= 0 && value <= 1, key, "must be between 0 and 1, got %g", value)
+}
+
+func (v *validator) hour(key string, value uint64) {
+	v.check(value < 24, key, "must be an hour of day (0-23), got %d", value)
+}
+
+func (v *validator) intRange(key string, r IntRange) {
+	v.nonNegative(key+".min", float64(r.Min))
+	v.check(r.Min <= r.Max, key+".max", "must not be less than %s.min (%d), got %d", key, r.Min, r.Max)
+}
+
+func (v *validator) floatRange(key string, r FloatRange) {
+	v.check(r.Min <= r.Max, key+".max", "must not be less than %s.min (%g), got %g", key, r.Min, r.Max)
+}
+
+func (v *validator) mortalityTable(key string, t MortalityTable) {
+	v.nonNegative(key+".accident", t.Accident)
+	v.nonNegative(key+".illness", t.Illness)
+	v.nonNegative(key+".old_age", t.OldAge)
+	v.nonNegative(key+".growth", t.Growth)
+}
+
+func (v *validator) mortalityModifier(key string, m MortalityModifier) {
+	conditions := 0
+	for _, condition := range []string{m.Target, m.Item, m.SplashTag} {
+		if condition != "" {
+			conditions++
+		}
+	}
+	v.check(conditions == 1, key, "must set exactly one of target, item and splash_tag")
+	v.check(m.Cause == "" || isDeathCause(m.Cause), key+".cause", "unknown cause %q (expected one of %v)", m.Cause, DeathCauses)
+	v.positive(key+".factor", m.Factor)
+}
+
+func (v *validator) illness(key string, i IllnessSpec) {
+	v.notEmpty(key+".name", i.Name)
+	v.nonNegative(key+".rate", i.Rate)
+	v.nonNegative(key+".age_growth", i.AgeGrowth)
+	v.positive(key+".duration_hours", float64(i.DurationHours))
+	v.probability(key+".severity", i.Severity)
+	v.positive(key+".mortality", i.Mortality)
+}
+
+func (v *validator) city(key string, c CitySpec) {
+	v.notEmpty(key+".name", c.Name)
+	v.positive(key+".population", float64(c.Population))
+	v.positive(key+".apartment_price", float64(c.ApartmentPrice))
+	v.check(len(c.Buildings) > 0, key+".buildings", "must contain at least one building")
+
+	housing := 0
+	for i, b := range c.Buildings {
+		bkey := fmt.Sprintf("%s.buildings[%d]", key, i)
+		v.check(isBuildingType(b.Type), bkey+".type", "unknown building type %q", b.Type)
+		v.positive(bkey+".capacity", float64(b.Capacity))
+		v.check(len(b.Jobs) == 0 || b.Type == "workplace", bkey+".jobs", "only workplaces can have jobs")
+		for j, job := range b.Jobs {
+			for k, vacancy := range job.Vacancies {
+				vkey := fmt.Sprintf("%s.jobs[%d].vacancies[%d]", bkey, j, k)
+				v.intRange(vkey+".salary", vacancy.Salary)
+				v.intRange(vkey+".positions", vacancy.Positions)
+			}
+		}
+		if b.Type == "residential_house" {
+			housing += b.Capacity
+		}
+	}
+	v.check(housing > 0, key+".buildings", "must contain at least one residential_house")
+}
+
+// isBuildingType проверяет, что тип здания известен
+func isBuildingType(buildingType string) bool {
+	for _, known := range BuildingTypes {
+		if buildingType == known {
+			return true
+		}
+	}
+	return false
+}
+
+// isPlannerType проверяет, что планировщик известен
+func isPlannerType(plannerType string) bool {
+	for _, known := range PlannerTypes {
+		if plannerType == known {
+			return true
+		}
+	}
+	return false
+}
+
+// isDeathCause проверяет, что причина смерти известна
+func isDeathCause(cause string) bool {
+	for _, known := range DeathCauses {
+		if cause == known {
+			return true
+		}
+	}
+	return false
+}
+
+// describeDecodeError переводит ошибку разбора JSON в сообщение с именем ключа или номером строки
+func describeDecodeError(data []byte, err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return fmt.Errorf("%s: expected %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value)
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line := 1 + bytes.Count(data[:syntaxErr.Offset], []byte("\n"))
+		return fmt.Errorf("line %d: %v", line, syntaxErr)
+	}
+
+	return err
+}
+
+// hasNestedKey сообщает, задан ли ключ key в JSON объекте section
+func hasNestedKey(section json.RawMessage, key string) bool {
+	var keys map[string]json.RawMessage
+	if json.Unmarshal(section, &keys) != nil {
+		return false
+	}
+	_, exists := keys[key]
+	return exists
+}
+
+// resolvePath разрешает относительный путь относительно каталога dir
+func resolvePath(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// isHeirGroup проверяет, что очередь наследников известна
+func isHeirGroup(group string) bool {
+	for _, known := range HeirGroups {
+		if group == known {
+			return true
+		}
+	}
+	return false
+}
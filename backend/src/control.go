@@ -0,0 +1,109 @@
+package src
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Состояния управляемой симуляции
+const (
+	StateIdle     = "idle"     // ждет команды start
+	StateRunning  = "running"  // выполняет часы подряд
+	StatePaused   = "paused"   // остановлена, можно выполнить отдельные шаги
+	StateFinished = "finished" // все часы выполнены
+)
+
+// Controller управляет ходом симуляции извне (через HTTP API).
+// Основной цикл вызывает waitTurn перед каждым часом и ждет разрешения
+type Controller struct {
+	mu    sync.Mutex
+	cond  *sync.Cond
+	state string
+	steps uint64 // сколько часов осталось выполнить в пошаговом режиме
+}
+
+// NewController создает контроллер в состоянии ожидания запуска
+func NewController() *Controller {
+	c := &Controller{state: StateIdle}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// State возвращает текущее состояние симуляции
+func (c *Controller) State() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state
+}
+
+// Start запускает симуляцию, ожидающую команды
+func (c *Controller) Start() error {
+	return c.transition(StateRunning, StateIdle)
+}
+
+// Pause приостанавливает симуляцию после текущего часа
+func (c *Controller) Pause() error {
+	return c.transition(StatePaused, StateRunning, StatePaused)
+}
+
+// Resume продолжает приостановленную симуляцию
+func (c *Controller) Resume() error {
+	return c.transition(StateRunning, StatePaused)
+}
+
+// Step выполняет hours часов и оставляет симуляцию на паузе
+func (c *Controller) Step(hours uint64) error {
+	if hours == 0 {
+		return fmt.Errorf("step must be positive")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state != StateIdle && c.state != StatePaused {
+		return fmt.Errorf("cannot step while simulation is %s", c.state)
+	}
+	c.state = StatePaused
+	c.steps += hours
+	c.cond.Broadcast()
+	return nil
+}
+
+// transition переводит симуляцию в состояние to, если текущее состояние входит в from
+func (c *Controller) transition(to string, from ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, state := range from {
+		if c.state == state {
+			c.state = to
+			c.steps = 0
+			c.cond.Broadcast()
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot switch to %s while simulation is %s", to, c.state)
+}
+
+// waitTurn блокирует основной цикл, пока не разрешено выполнить следующий час
+func (c *Controller) waitTurn() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for c.state == StateIdle || (c.state == StatePaused && c.steps == 0) {
+		c.cond.Wait()
+	}
+	if c.state == StatePaused {
+		c.steps--
+	}
+}
+
+// finish отмечает, что все часы симуляции выполнены
+func (c *Controller) finish() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.state = StateFinished
+	c.steps = 0
+	c.cond.Broadcast()
+}
@@ -1,81 +1,92 @@
 package src
 
 import (
+	"bufio"
 	"encoding/csv"
 	"fmt"
 	"os"
 	"strconv"
-
-	"github.com/fallra1n/humanity/src/components"
 )
 
-// logToCSV записывает текущее состояние всех людей в CSV файл
-func logToCSV(people []*components.Human, hour uint64) error {
-	filename := "log.csv"
+// csvHeader - колонки CSV журнала состояния людей
+var csvHeader = []string{"hour", "agent_id", "age", "gender", "alive", "money", "location", "building_type", "job_status", "marital_status", "geo"}
 
-	// Проверить, существует ли файл, чтобы определить, нужно ли писать заголовки
-	fileExists := false
-	if _, err := os.Stat(filename); err == nil {
-		fileExists = true
-	}
+// CSVSink записывает состояние людей в CSV файл (одна строка на человека за час)
+type CSVSink struct {
+	file   *os.File
+	buffer *bufio.Writer
+	writer *csv.Writer
+}
 
-	file, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+// NewCSVSink открывает CSV файл. Заголовок пишется, только если файл пуст
+func NewCSVSink(path string, appendMode bool) (*CSVSink, error) {
+	file, empty, err := openOutputFile(path, appendMode)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer file.Close()
-
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
 
-	// Записать заголовок если это первый раз
-	if !fileExists {
-		header := []string{"hour", "agent_id", "age", "gender", "alive", "money", "location", "building_type", "job_status", "marital_status", "geo"}
-		if err := writer.Write(header); err != nil {
-			return err
-		}
+	buffer := bufio.NewWriter(file)
+	sink := &CSVSink{
+		file:   file,
+		buffer: buffer,
+		writer: csv.NewWriter(buffer),
 	}
 
-	// Записать данные для каждого человека
-	for _, person := range people {
-		location := "unknown"
-		buildingType := "unknown"
-
-		if person.CurrentBuilding != nil {
-			location = person.CurrentBuilding.Name
-			buildingType = string(person.CurrentBuilding.Type)
-		}
-
-		jobStatus := "unemployed"
-		if person.Job != nil {
-			jobStatus = "employed"
+	if empty {
+		if err := sink.writer.Write(csvHeader); err != nil {
+			file.Close()
+			return nil, err
 		}
+	}
+	return sink, nil
+}
 
-		// Получаем координаты здания
+// WriteHour записывает состояние всех людей за час
+func (s *CSVSink) WriteHour(records []AgentRecord) error {
+	for _, record := range records {
+		// Координаты здания (пусто, если человек вне здания)
 		geoCoords := ""
-		if person.CurrentBuilding != nil {
-			lat, lon := person.CurrentBuilding.GetCoordinates()
-			geoCoords = fmt.Sprintf("%.6f,%.6f", lat, lon)
+		if record.HasGeo {
+			geoCoords = fmt.Sprintf("%.6f,%.6f", record.Lat, record.Lon)
 		}
 
 		row := []string{
-			strconv.FormatUint(hour, 10),
-			strconv.Itoa(components.GlobalHumanStorage.Get(person)),
-			fmt.Sprintf("%.2f", person.Age),
-			string(person.Gender),
-			fmt.Sprintf("%t", !person.Dead),
-			strconv.FormatInt(person.Money, 10),
-			location,
-			buildingType,
-			jobStatus,
-			string(person.MaritalStatus),
+			strconv.FormatUint(record.Hour, 10),
+			strconv.Itoa(record.AgentID),
+			fmt.Sprintf("%.2f", record.Age),
+			record.Gender,
+			fmt.Sprintf("%t", record.Alive),
+			strconv.FormatInt(record.Money, 10),
+			record.Location,
+			record.BuildingType,
+			record.JobStatus,
+			record.MaritalStatus,
 			geoCoords,
 		}
 
-		if err := writer.Write(row); err != nil {
+		if err := s.writer.Write(row); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	// Сбросить данные на диск после каждого часа, чтобы файл можно было читать во время симуляции
+	s.writer.Flush()
+	if err := s.writer.Error(); err != nil {
+		return err
+	}
+	return s.buffer.Flush()
+}
+
+// Close сбрасывает буфер и закрывает файл
+func (s *CSVSink) Close() error {
+	s.writer.Flush()
+	if err := s.writer.Error(); err != nil {
+		s.file.Close()
+		return err
+	}
+	if err := s.buffer.Flush(); err != nil {
+		s.file.Close()
+		return err
+	}
+	return s.file.Close()
 }
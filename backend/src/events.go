@@ -0,0 +1,52 @@
+package src
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/fallra1n/humanity/src/components"
+)
+
+// EventLog записывает события симуляции в файл в формате JSONL (одно событие на строку)
+type EventLog struct {
+	file    *os.File
+	writer  *bufio.Writer
+	encoder *json.Encoder
+	failed  bool
+}
+
+// NewEventLog создает (или перезаписывает) файл событий
+func NewEventLog(path string) (*EventLog, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event log %s: %v", path, err)
+	}
+
+	writer := bufio.NewWriter(file)
+	return &EventLog{
+		file:    file,
+		writer:  writer,
+		encoder: json.NewEncoder(writer),
+	}, nil
+}
+
+// Record записывает одно событие
+func (l *EventLog) Record(event components.Event) {
+	if err := l.encoder.Encode(event); err != nil && !l.failed {
+		// Сообщить об ошибке один раз, чтобы не засорять вывод
+		log.Printf("Warning: Failed to write event: %v", err)
+		l.failed = true
+	}
+}
+
+// Close сбрасывает буфер и закрывает файл
+func (l *EventLog) Close() error {
+	if err := l.writer.Flush(); err != nil {
+		l.file.Close()
+		return fmt.Errorf("failed to write event log: %v", err)
+	}
+	return l.file.Close()
+}
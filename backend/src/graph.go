@@ -0,0 +1,232 @@
+package src
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fallra1n/humanity/src/components"
+)
+
+// Граф целей (команда humanity graph): глобальные цели связаны с локальными,
+// а локальные - с действиями по общим тегам, как при загрузке конфигурации.
+// Граф выводится в Graphviz DOT или JSON
+
+// Виды узлов графа целей
+const (
+	GraphGlobalTarget = "global_target"
+	GraphLocalTarget  = "local_target"
+	GraphAction       = "action"
+)
+
+// GraphNode - узел графа целей. Поля целей и действий заполняются только для своего вида узлов
+type GraphNode struct {
+	ID     string   `json:"id"`
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	Tags   []string `json:"tags"`
+	Power  *float64 `json:"power,omitempty"`
+	Weight *float64 `json:"weight,omitempty"`
+	Stages []string `json:"stages,omitempty"`
+
+	Price      *int64           `json:"price,omitempty"`
+	Time       *int64           `json:"time,omitempty"`
+	BonusMoney int64            `json:"bonus_money,omitempty"`
+	Building   string           `json:"building,omitempty"`
+	Rules      []string         `json:"rules,omitempty"`
+	Items      map[string]int64 `json:"items,omitempty"`
+	Consumes   map[string]int64 `json:"consumes,omitempty"`
+	Effects    []string         `json:"effects,omitempty"`
+}
+
+// GraphEdge - связь цели с локальной целью или действием по общим тегам
+type GraphEdge struct {
+	From string   `json:"from"`
+	To   string   `json:"to"`
+	Tags []string `json:"tags"`
+}
+
+// GoalGraph - граф целей в порядке записи в файлах конфигурации
+type GoalGraph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// LoadGoalGraph загружает конфигурацию так же, как симуляция, и строит граф целей
+func LoadGoalGraph(actionsFile, localFile, globalFile string) (*GoalGraph, error) {
+	actions, err := LoadActions(actionsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load actions: %v", err)
+	}
+	localTargets, err := LoadLocalTargets(localFile, actions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load local targets: %v", err)
+	}
+	globalTargets, err := LoadGlobalTargets(globalFile, localTargets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load global targets: %v", err)
+	}
+
+	return NewGoalGraph(actions, localTargets, globalTargets), nil
+}
+
+// NewGoalGraph строит граф целей по загруженным определениям
+func NewGoalGraph(actions []*components.Action, localTargets []*components.LocalTarget, globalTargets []*components.GlobalTarget) *GoalGraph {
+	graph := &GoalGraph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+
+	for _, target := range globalTargets {
+		power, weight := target.Power, target.Weight
+		node := GraphNode{
+			ID:     graphID(GraphGlobalTarget, target.Name),
+			Kind:   GraphGlobalTarget,
+			Name:   target.Name,
+			Tags:   sortedKeys(target.Tags),
+			Power:  &power,
+			Weight: &weight,
+		}
+		for _, stage := range components.LifeStages {
+			if target.Stages[stage] {
+				node.Stages = append(node.Stages, string(stage))
+			}
+		}
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	for _, target := range localTargets {
+		graph.Nodes = append(graph.Nodes, GraphNode{
+			ID:   graphID(GraphLocalTarget, target.Name),
+			Kind: GraphLocalTarget,
+			Name: target.Name,
+			Tags: sortedKeys(target.Tags),
+		})
+	}
+	for _, action := range actions {
+		price, time := action.Price, action.TimeToExecute
+		node := GraphNode{
+			ID:         graphID(GraphAction, action.Name),
+			Kind:       GraphAction,
+			Name:       action.Name,
+			Tags:       sortedKeys(action.Tags),
+			Price:      &price,
+			Time:       &time,
+			BonusMoney: action.BonusMoney,
+			Building:   string(action.BuildingType),
+		}
+		for _, rule := range action.Rules {
+			node.Rules = append(node.Rules, rule.String())
+		}
+		for _, effect := range action.Effects {
+			node.Effects = append(node.Effects, effect.String())
+		}
+		if len(action.Items) > 0 {
+			node.Items = action.Items
+		}
+		if len(action.RemovableItems) > 0 {
+			node.Consumes = action.RemovableItems
+		}
+		graph.Nodes = append(graph.Nodes, node)
+	}
+
+	// Ребра в порядке записи целей и действий, а не в порядке обхода карт
+	for _, global := range globalTargets {
+		for _, local := range localTargets {
+			if global.TargetsPossible[local] {
+				graph.addEdge(graphID(GraphGlobalTarget, global.Name), graphID(GraphLocalTarget, local.Name), global.Tags, local.Tags)
+			}
+		}
+	}
+	for _, local := range localTargets {
+		for _, action := range actions {
+			if local.ActionsPossible[action] {
+				graph.addEdge(graphID(GraphLocalTarget, local.Name), graphID(GraphAction, action.Name), local.Tags, action.Tags)
+			}
+		}
+	}
+
+	return graph
+}
+
+func (g *GoalGraph) addEdge(from, to string, fromTags, toTags map[string]bool) {
+	var shared []string
+	for _, tag := range sortedKeys(fromTags) {
+		if toTags[tag] {
+			shared = append(shared, tag)
+		}
+	}
+	g.Edges = append(g.Edges, GraphEdge{From: from, To: to, Tags: shared})
+}
+
+// WriteJSON записывает граф в JSON
+func (g *GoalGraph) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(g)
+}
+
+// WriteDOT записывает граф в формате Graphviz DOT (слева направо: глобальные цели, локальные, действия)
+func (g *GoalGraph) WriteDOT(w io.Writer) error {
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintln(out, "digraph goals {")
+	fmt.Fprintln(out, "\trankdir=LR;")
+	fmt.Fprintln(out, "\tnode [fontname=\"Helvetica\"];")
+	fmt.Fprintln(out, "\tedge [fontname=\"Helvetica\", fontsize=10];")
+
+	for _, node := range g.Nodes {
+		lines := []string{node.Name}
+		var shape string
+		switch node.Kind {
+		case GraphGlobalTarget:
+			shape = "doubleoctagon"
+			lines = append(lines, fmt.Sprintf("power %g, weight %g", *node.Power, *node.Weight))
+			if len(node.Stages) > 0 {
+				lines = append(lines, strings.Join(node.Stages, ", "))
+			}
+		case GraphLocalTarget:
+			shape = "box"
+		default:
+			shape = "ellipse"
+			details := fmt.Sprintf("price %d, %d h", *node.Price, *node.Time)
+			if node.Building != "" {
+				details += ", " + node.Building
+			}
+			lines = append(lines, details)
+			for _, rule := range node.Rules {
+				lines = append(lines, "$"+rule)
+			}
+			for _, item := range sortedKeys(node.Consumes) {
+				lines = append(lines, "$-"+item)
+			}
+			for _, item := range sortedKeys(node.Items) {
+				lines = append(lines, "@"+item)
+			}
+			for _, effect := range node.Effects {
+				lines = append(lines, "!"+effect)
+			}
+		}
+		fmt.Fprintf(out, "\t%s [shape=%s, label=%s];\n", dotQuote(node.ID), shape, dotLabel(lines))
+	}
+
+	for _, edge := range g.Edges {
+		fmt.Fprintf(out, "\t%s -> %s [label=%s];\n", dotQuote(edge.From), dotQuote(edge.To), dotQuote(strings.Join(edge.Tags, ", ")))
+	}
+
+	fmt.Fprintln(out, "}")
+	return out.Flush()
+}
+
+// graphID - идентификатор узла; имена действий и целей могут совпадать, поэтому с префиксом вида
+func graphID(kind, name string) string {
+	return kind + ":" + name
+}
+
+// dotQuote записывает строку в кавычках DOT
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// dotLabel собирает многострочную подпись узла
+func dotLabel(lines []string) string {
+	return dotQuote(strings.Join(lines, "\n"))
+}
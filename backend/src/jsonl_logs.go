@@ -0,0 +1,81 @@
+package src
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+)
+
+// jsonlRecord - строка JSONL журнала состояния людей
+type jsonlRecord struct {
+	Hour          uint64   `json:"hour"`
+	AgentID       int      `json:"agent_id"`
+	Age           float64  `json:"age"`
+	Gender        string   `json:"gender"`
+	Alive         bool     `json:"alive"`
+	Money         int64    `json:"money"`
+	Location      string   `json:"location"`
+	BuildingType  string   `json:"building_type"`
+	JobStatus     string   `json:"job_status"`
+	MaritalStatus string   `json:"marital_status"`
+	Lat           *float64 `json:"lat,omitempty"`
+	Lon           *float64 `json:"lon,omitempty"`
+}
+
+// JSONLSink записывает состояние людей в формате JSONL (один объект на человека за час)
+type JSONLSink struct {
+	file    *os.File
+	writer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+// NewJSONLSink открывает JSONL файл
+func NewJSONLSink(path string, appendMode bool) (*JSONLSink, error) {
+	file, _, err := openOutputFile(path, appendMode)
+	if err != nil {
+		return nil, err
+	}
+
+	writer := bufio.NewWriter(file)
+	return &JSONLSink{
+		file:    file,
+		writer:  writer,
+		encoder: json.NewEncoder(writer),
+	}, nil
+}
+
+// WriteHour записывает состояние всех людей за час
+func (s *JSONLSink) WriteHour(records []AgentRecord) error {
+	for _, record := range records {
+		line := jsonlRecord{
+			Hour:          record.Hour,
+			AgentID:       record.AgentID,
+			Age:           record.Age,
+			Gender:        record.Gender,
+			Alive:         record.Alive,
+			Money:         record.Money,
+			Location:      record.Location,
+			BuildingType:  record.BuildingType,
+			JobStatus:     record.JobStatus,
+			MaritalStatus: record.MaritalStatus,
+		}
+		if record.HasGeo {
+			lat, lon := record.Lat, record.Lon
+			line.Lat, line.Lon = &lat, &lon
+		}
+
+		if err := s.encoder.Encode(line); err != nil {
+			return err
+		}
+	}
+	return s.writer.Flush()
+}
+
+// Close сбрасывает буфер и закрывает файл
+func (s *JSONLSink) Close() error {
+	if err := s.writer.Flush(); err != nil {
+		s.file.Close()
+		return err
+	}
+	return s.file.Close()
+}
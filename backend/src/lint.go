@@ -0,0 +1,334 @@
+package src
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fallra1n/humanity/src/components"
+	"github.com/fallra1n/humanity/src/utils"
+)
+
+// Проверка конфигурации (команда humanity lint).
+// Опечатки в actions.ini, local.ini и global.ini не ломают загрузку, а молча создают тупики:
+// цель, которую нечем закрыть, или действие, для которого никогда не будет предмета.
+// Линтер загружает файлы так же, как симуляция, и ищет такие места
+
+// LintSeverity - серьезность найденной проблемы
+type LintSeverity string
+
+const (
+	LintError   LintSeverity = "error"   // конфигурация заведомо не работает как задумано
+	LintWarning LintSeverity = "warning" // подозрительное место, возможно опечатка
+)
+
+// LintIssue - проблема в конфигурации
+type LintIssue struct {
+	File     string
+	Line     int
+	Severity LintSeverity
+	Message  string
+}
+
+// String возвращает проблему в виде файл:строка: серьезность: сообщение
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s", i.File, i.Line, i.Severity, i.Message)
+}
+
+// linter собирает проблемы и места определения действий и целей
+type linter struct {
+	issues      []LintIssue
+	actionLines map[*components.Action]int
+	localLines  map[*components.LocalTarget]int
+	globalLines map[*components.GlobalTarget]int
+}
+
+// report добавляет проблему; одинаковые проблемы выводятся один раз
+func (l *linter) report(file string, line int, severity LintSeverity, format string, args ...interface{}) {
+	issue := LintIssue{File: file, Line: line, Severity: severity, Message: fmt.Sprintf(format, args...)}
+	for _, reported := range l.issues {
+		if reported == issue {
+			return
+		}
+	}
+	l.issues = append(l.issues, issue)
+}
+
+// LintConfig проверяет файлы действий, локальных и глобальных целей.
+// Ошибка возвращается только если файл не удалось прочитать
+func LintConfig(actionsFile, localFile, globalFile string) ([]LintIssue, error) {
+	l := &linter{
+		actionLines: make(map[*components.Action]int),
+		localLines:  make(map[*components.LocalTarget]int),
+		globalLines: make(map[*components.GlobalTarget]int),
+	}
+
+	actions, err := l.loadActions(actionsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Цели загружаются штатными загрузчиками; строки берутся из того же разбора файла
+	localSequences, err := utils.LoadNumberedSequencesFromFile(localFile)
+	if err != nil {
+		return nil, err
+	}
+	localTargets, err := LoadLocalTargets(localFile, actions)
+	if err != nil {
+		l.report(localFile, 0, LintError, "%v", err)
+		return l.issues, nil
+	}
+	for i, target := range localTargets {
+		l.localLines[target] = localSequences[i].Line
+	}
+
+	globalSequences, err := utils.LoadNumberedSequencesFromFile(globalFile)
+	if err != nil {
+		return nil, err
+	}
+	globalTargets, err := LoadGlobalTargets(globalFile, localTargets)
+	if err != nil {
+		l.report(globalFile, 0, LintError, "%v", err)
+		return l.issues, nil
+	}
+	for i, target := range globalTargets {
+		l.globalLines[target] = globalSequences[i].Line
+	}
+
+	possible := l.checkActions(actionsFile, actions, localTargets)
+	reachable := l.checkLocalTargets(localFile, localTargets, possible, globalTargets)
+	l.checkGlobalTargets(globalFile, globalTargets, reachable)
+
+	// Проблемы выводятся в порядке файлов и строк
+	rank := map[string]int{actionsFile: 0, localFile: 1, globalFile: 2}
+	sort.SliceStable(l.issues, func(i, j int) bool {
+		a, b := l.issues[i], l.issues[j]
+		if rank[a.File] != rank[b.File] {
+			return rank[a.File] < rank[b.File]
+		}
+		return a.Line < b.Line
+	})
+
+	return l.issues, nil
+}
+
+// loadActions разбирает actions.ini построчно, как LoadActions, но не останавливается на первой ошибке
+func (l *linter) loadActions(filename string) ([]*components.Action, error) {
+	sequences, err := utils.LoadNumberedSequencesFromFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var actions []*components.Action
+	for _, sequence := range sequences {
+		where := fmt.Sprintf("%s:%d", filename, sequence.Line)
+		action, err := parseAction(where, sequence.Words)
+		if err != nil {
+			l.report(filename, sequence.Line, LintError, "%s", strings.TrimPrefix(err.Error(), where+": "))
+			continue
+		}
+		l.actionLines[action] = sequence.Line
+		actions = append(actions, action)
+	}
+
+	return actions, nil
+}
+
+// checkActions проверяет действия и возвращает те, что могут быть когда-либо выполнены
+func (l *linter) checkActions(filename string, actions []*components.Action, localTargets []*components.LocalTarget) map[*components.Action]bool {
+	// Предметы, которые производит хоть какое-то действие
+	producers := make(map[string]bool)
+	for _, action := range actions {
+		for item := range action.Items {
+			producers[item] = true
+		}
+	}
+
+	// Выполнимые действия: удаляемые предметы должны производиться выполнимыми действиями
+	possible := make(map[*components.Action]bool)
+	produced := make(map[string]bool)
+	for changed := true; changed; {
+		changed = false
+		for _, action := range actions {
+			if possible[action] || !hasItems(produced, action.RemovableItems) {
+				continue
+			}
+			possible[action] = true
+			for item := range action.Items {
+				produced[item] = true
+			}
+			changed = true
+		}
+	}
+
+	localTags := make(map[string]bool)
+	for _, target := range localTargets {
+		for tag := range target.Tags {
+			localTags[tag] = true
+		}
+	}
+
+	firstLine := make(map[string]int)
+	for _, action := range actions {
+		line := l.actionLines[action]
+
+		if first, exists := firstLine[action.Name]; exists {
+			l.report(filename, line, LintError, "duplicate action name %s (first defined at line %d)", action.Name, first)
+		} else {
+			firstLine[action.Name] = line
+		}
+
+		for _, item := range sortedKeys(action.RemovableItems) {
+			if !producers[item] {
+				l.report(filename, line, LintError, "action %s consumes item %s, but no action produces it", action.Name, item)
+			} else if !produced[item] {
+				l.report(filename, line, LintError, "action %s consumes item %s, which only actions that can never be executed produce", action.Name, item)
+			}
+		}
+
+		for _, rule := range action.Rules {
+			for _, item := range rule.Items() {
+				if !producers[item] {
+					l.report(filename, line, LintWarning, "rule $%s of action %s refers to item %s, but no action produces it", rule, action.Name, item)
+				}
+			}
+		}
+
+		var unusedTags []string
+		for _, tag := range sortedKeys(action.Tags) {
+			if !localTags[tag] {
+				unusedTags = append(unusedTags, tag)
+			}
+		}
+		if len(unusedTags) == len(action.Tags) {
+			l.report(filename, line, LintWarning, "action %s is not used by any local target (tags: %s)", action.Name, strings.Join(unusedTags, ", "))
+		} else if len(unusedTags) > 0 {
+			l.report(filename, line, LintWarning, "action %s: %s not used by any local target", action.Name, tagList(unusedTags))
+		}
+	}
+
+	return possible
+}
+
+// checkLocalTargets проверяет локальные цели и возвращает достижимые.
+// Локальная цель выполнена, когда все ее теги закрыты выполненными действиями
+func (l *linter) checkLocalTargets(filename string, targets []*components.LocalTarget, possible map[*components.Action]bool,
+	globalTargets []*components.GlobalTarget) map[*components.LocalTarget]bool {
+
+	globalTags := make(map[string]bool)
+	for _, target := range globalTargets {
+		for tag := range target.Tags {
+			globalTags[tag] = true
+		}
+	}
+
+	reachable := make(map[*components.LocalTarget]bool)
+	firstLine := make(map[string]int)
+	for _, target := range targets {
+		line := l.localLines[target]
+
+		if first, exists := firstLine[target.Name]; exists {
+			l.report(filename, line, LintError, "duplicate local target name %s (first defined at line %d)", target.Name, first)
+		} else {
+			firstLine[target.Name] = line
+		}
+
+		var missing, blocked []string
+		for _, tag := range sortedKeys(target.Tags) {
+			tagged, executable := false, false
+			for action := range target.ActionsPossible {
+				if action.Tags[tag] {
+					tagged = true
+					executable = executable || possible[action]
+				}
+			}
+			if !tagged {
+				missing = append(missing, tag)
+			} else if !executable {
+				blocked = append(blocked, tag)
+			}
+		}
+		if reasons := unreachableReasons(missing, "no action has", blocked, "only actions that can never be executed have"); reasons != "" {
+			l.report(filename, line, LintError, "local target %s is unreachable: %s", target.Name, reasons)
+		}
+		reachable[target] = len(missing) == 0 && len(blocked) == 0
+
+		var unusedTags []string
+		for _, tag := range sortedKeys(target.Tags) {
+			if !globalTags[tag] {
+				unusedTags = append(unusedTags, tag)
+			}
+		}
+		if len(unusedTags) == len(target.Tags) {
+			l.report(filename, line, LintWarning, "local target %s is not used by any global target (tags: %s)", target.Name, strings.Join(unusedTags, ", "))
+		} else if len(unusedTags) > 0 {
+			l.report(filename, line, LintWarning, "local target %s: %s not used by any global target", target.Name, tagList(unusedTags))
+		}
+	}
+
+	return reachable
+}
+
+// checkGlobalTargets проверяет, что все теги глобальных целей закрываются достижимыми локальными целями
+func (l *linter) checkGlobalTargets(filename string, targets []*components.GlobalTarget, reachable map[*components.LocalTarget]bool) {
+	firstLine := make(map[string]int)
+	for _, target := range targets {
+		line := l.globalLines[target]
+
+		if first, exists := firstLine[target.Name]; exists {
+			l.report(filename, line, LintError, "duplicate global target name %s (first defined at line %d)", target.Name, first)
+		} else {
+			firstLine[target.Name] = line
+		}
+
+		var missing, blocked []string
+		for _, tag := range sortedKeys(target.Tags) {
+			tagged, completable := false, false
+			for local := range target.TargetsPossible {
+				if local.Tags[tag] {
+					tagged = true
+					completable = completable || reachable[local]
+				}
+			}
+			if !tagged {
+				missing = append(missing, tag)
+			} else if !completable {
+				blocked = append(blocked, tag)
+			}
+		}
+		if reasons := unreachableReasons(missing, "no local target has", blocked, "only unreachable local targets have"); reasons != "" {
+			l.report(filename, line, LintError, "global target %s is unreachable: %s", target.Name, reasons)
+		}
+	}
+}
+
+// unreachableReasons объединяет причины недостижимости цели в одно сообщение
+// (пусто - цель достижима): теги, которых нет совсем, и теги, которые нечем закрыть
+func unreachableReasons(missing []string, missingReason string, blocked []string, blockedReason string) string {
+	var reasons []string
+	if len(missing) > 0 {
+		reasons = append(reasons, missingReason+" "+tagList(missing))
+	}
+	if len(blocked) > 0 {
+		reasons = append(reasons, blockedReason+" "+tagList(blocked))
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// hasItems проверяет, что все предметы из списка есть среди доступных
+func hasItems(available map[string]bool, items map[string]int64) bool {
+	for item := range items {
+		if !available[item] {
+			return false
+		}
+	}
+	return true
+}
+
+// tagList форматирует список тегов для сообщения: "tag a" или "tags a, b"
+func tagList(tags []string) string {
+	if len(tags) == 1 {
+		return "tag " + tags[0]
+	}
+	return "tags " + strings.Join(tags, ", ")
+}
@@ -0,0 +1,59 @@
+package src
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Конфигурация репозитория должна проходить собственную проверку без ошибок
+func TestLintRepoConfig(t *testing.T) {
+	issues, err := LintConfig("../actions.ini", "../local.ini", "../global.ini")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, issue := range issues {
+		if issue.Severity == LintError {
+			t.Errorf("%s", issue)
+		}
+	}
+}
+
+// Недостижимая цель дает одно сообщение со всеми причинами
+func TestLintUnreachableTargetReportedOnce(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	actions := write("actions.ini", "paint 0 1 art\n")
+	local := write("local.ini", "draw art\nsculpt stone\n")
+	global := write("global.ini", "artist 1.0 art stone fame\n")
+
+	issues, err := LintConfig(actions, local, global)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var errors []string
+	for _, issue := range issues {
+		if issue.Severity == LintError {
+			errors = append(errors, issue.String())
+		}
+	}
+	want := []string{
+		local + ":2: error: local target sculpt is unreachable: no action has tag stone",
+		global + ":1: error: global target artist is unreachable: no local target has tag fame; only unreachable local targets have tag stone",
+	}
+	if len(errors) != len(want) {
+		t.Fatalf("errors = %q, want %q", errors, want)
+	}
+	for i := range want {
+		if errors[i] != want[i] {
+			t.Errorf("error %d = %q, want %q", i, errors[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package src
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/fallra1n/humanity/src/components"
+)
+
+// Форматы вывода состояния людей
+const (
+	OutputCSV     = "csv"
+	OutputJSONL   = "jsonl"
+	OutputParquet = "parquet"
+)
+
+// AgentRecord - состояние одного человека на конец часа
+type AgentRecord struct {
+	Hour          uint64
+	AgentID       int
+	Age           float64
+	Gender        string
+	Alive         bool
+	Money         int64
+	Location      string // название текущего здания ("unknown" - вне здания)
+	BuildingType  string
+	JobStatus     string // employed или unemployed
+	MaritalStatus string
+	HasGeo        bool // координаты известны только внутри здания
+	Lat, Lon      float64
+}
+
+// OutputSink принимает снимок состояния всех людей за час
+type OutputSink interface {
+	WriteHour(records []AgentRecord) error
+	Close() error
+}
+
+// OutputSpec описывает один приемник вывода: формат и файл
+type OutputSpec struct {
+	Format string
+	Path   string
+}
+
+// ParseOutputSpec разбирает строку вида "формат:путь", например "parquet:out/log.parquet"
+func ParseOutputSpec(value string) (OutputSpec, error) {
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return OutputSpec{}, fmt.Errorf("invalid output %q: expected format:path", value)
+	}
+
+	spec := OutputSpec{Format: strings.ToLower(parts[0]), Path: parts[1]}
+	switch spec.Format {
+	case OutputCSV, OutputJSONL, OutputParquet:
+		return spec, nil
+	default:
+		return OutputSpec{}, fmt.Errorf("invalid output %q: unknown format %q (expected csv, jsonl or parquet)", value, parts[0])
+	}
+}
+
+// String возвращает спецификацию в виде "формат:путь"
+func (s OutputSpec) String() string {
+	return s.Format + ":" + s.Path
+}
+
+// OpenOutputSink открывает приемник вывода. Если appendMode == false, файл перезаписывается
+func OpenOutputSink(spec OutputSpec, appendMode bool) (OutputSink, error) {
+	switch spec.Format {
+	case OutputCSV:
+		return NewCSVSink(spec.Path, appendMode)
+	case OutputJSONL:
+		return NewJSONLSink(spec.Path, appendMode)
+	case OutputParquet:
+		return NewParquetSink(spec.Path, appendMode)
+	default:
+		return nil, fmt.Errorf("unknown output format %q", spec.Format)
+	}
+}
+
+// openOutputFile открывает файл вывода на запись с начала или в конец.
+// Возвращает также признак того, что файл пуст (нужно писать заголовок)
+func openOutputFile(path string, appendMode bool) (*os.File, bool, error) {
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if appendMode {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+
+	file, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to open output %s: %v", path, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, false, fmt.Errorf("failed to open output %s: %v", path, err)
+	}
+	return file, info.Size() == 0, nil
+}
+
+// snapshotPeople собирает состояние всех людей на конец часа
+func snapshotPeople(people []*components.Human, hour uint64) []AgentRecord {
+	records := make([]AgentRecord, 0, len(people))
+	for _, person := range people {
+		record := AgentRecord{
+			Hour:          hour,
+			AgentID:       components.GlobalHumanStorage.Get(person),
+			Age:           person.Age,
+			Gender:        string(person.Gender),
+			Alive:         !person.Dead,
+			Money:         person.Money,
+			Location:      "unknown",
+			BuildingType:  "unknown",
+			JobStatus:     "unemployed",
+			MaritalStatus: string(person.MaritalStatus),
+		}
+
+		if person.CurrentBuilding != nil {
+			record.Location = person.CurrentBuilding.Name
+			record.BuildingType = string(person.CurrentBuilding.Type)
+			record.HasGeo = true
+			record.Lat, record.Lon = person.CurrentBuilding.GetCoordinates()
+		}
+
+		if person.Job != nil {
+			record.JobStatus = "employed"
+		}
+
+		records = append(records, record)
+	}
+	return records
+}
+
+// openOutputs открывает все приемники вывода симуляции
+func (s *Simulation) openOutputs() error {
+	for _, spec := range s.Outputs {
+		sink, err := OpenOutputSink(spec, s.OutputAppend)
+		if err != nil {
+			s.closeOutputs()
+			return err
+		}
+		s.outputs = append(s.outputs, sink)
+	}
+	return nil
+}
+
+// writeOutputs записывает состояние людей во все приемники (раз в OutputEvery часов)
+func (s *Simulation) writeOutputs(hour uint64) {
+	if len(s.outputs) == 0 {
+		return
+	}
+	if s.OutputEvery > 1 && hour%s.OutputEvery != 0 {
+		return
+	}
+
+	records := snapshotPeople(s.people, hour)
+	for i, sink := range s.outputs {
+		if err := sink.WriteHour(records); err != nil {
+			log.Printf("Warning: Failed to write to %s: %v", s.Outputs[i], err)
+		}
+	}
+}
+
+// closeOutputs закрывает все открытые приемники вывода
+func (s *Simulation) closeOutputs() {
+	for i, sink := range s.outputs {
+		if err := sink.Close(); err != nil {
+			log.Printf("Warning: Failed to close %s: %v", s.Outputs[i], err)
+		}
+	}
+	s.outputs = nil
+}
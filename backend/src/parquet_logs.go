@@ -0,0 +1,453 @@
+package src
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+)
+
+// Минимальная запись файлов Parquet без внешних зависимостей.
+// Каждая группа строк содержит по одной странице данных на колонку,
+// значения закодированы PLAIN без сжатия, метаданные - в компактном протоколе Thrift
+
+// Физические типы Parquet
+const (
+	parquetBoolean   int32 = 0
+	parquetInt32     int32 = 1
+	parquetInt64     int32 = 2
+	parquetDouble    int32 = 5
+	parquetByteArray int32 = 6
+)
+
+// Кодировки и прочие значения перечислений Parquet
+const (
+	parquetEncodingPlain int32 = 0
+	parquetEncodingRLE   int32 = 3
+	parquetRequired      int32 = 0
+	parquetOptional      int32 = 1
+	parquetConvertedUTF8 int32 = 0
+	parquetDataPage      int32 = 0
+	parquetUncompressed  int32 = 0
+)
+
+// parquetMagic - сигнатура в начале и в конце файла
+const parquetMagic = "PAR1"
+
+// parquetRowGroupSize - сколько строк накапливается в памяти перед записью группы строк
+const parquetRowGroupSize = 100000
+
+// parquetColumn накапливает значения одной колонки текущей группы строк
+type parquetColumn struct {
+	name     string
+	kind     int32
+	utf8     bool
+	optional bool
+
+	values  bytes.Buffer // значения в кодировке PLAIN (кроме BOOLEAN)
+	bools   []bool       // значения BOOLEAN (упаковываются по битам при записи)
+	defined []bool       // уровни определения для optional колонок
+}
+
+// parquetChunk - положение записанной колонки в файле
+type parquetChunk struct {
+	offset    int64
+	size      int64
+	numValues int64
+}
+
+// parquetRowGroup - метаданные записанной группы строк
+type parquetRowGroup struct {
+	chunks  []parquetChunk
+	size    int64
+	numRows int64
+}
+
+// ParquetSink записывает состояние людей в файл Parquet
+type ParquetSink struct {
+	file   *os.File
+	writer *bufio.Writer
+	offset int64
+
+	columns   []*parquetColumn
+	rows      int64 // строк в текущей группе
+	rowGroups []parquetRowGroup
+	totalRows int64
+}
+
+// NewParquetSink создает файл Parquet. Дописывать в существующий файл нельзя:
+// метаданные Parquet хранятся в конце файла
+func NewParquetSink(path string, appendMode bool) (*ParquetSink, error) {
+	if appendMode {
+		return nil, fmt.Errorf("parquet output %s does not support append", path)
+	}
+
+	file, _, err := openOutputFile(path, false)
+	if err != nil {
+		return nil, err
+	}
+
+	sink := &ParquetSink{
+		file:   file,
+		writer: bufio.NewWriter(file),
+		columns: []*parquetColumn{
+			{name: "hour", kind: parquetInt64},
+			{name: "agent_id", kind: parquetInt32},
+			{name: "age", kind: parquetDouble},
+			{name: "gender", kind: parquetByteArray, utf8: true},
+			{name: "alive", kind: parquetBoolean},
+			{name: "money", kind: parquetInt64},
+			{name: "location", kind: parquetByteArray, utf8: true},
+			{name: "building_type", kind: parquetByteArray, utf8: true},
+			{name: "job_status", kind: parquetByteArray, utf8: true},
+			{name: "marital_status", kind: parquetByteArray, utf8: true},
+			{name: "lat", kind: parquetDouble, optional: true},
+			{name: "lon", kind: parquetDouble, optional: true},
+		},
+	}
+
+	if err := sink.write([]byte(parquetMagic)); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return sink, nil
+}
+
+// WriteHour добавляет состояние всех людей за час в текущую группу строк
+func (s *ParquetSink) WriteHour(records []AgentRecord) error {
+	for _, record := range records {
+		c := s.columns
+		c[0].appendInt64(int64(record.Hour))
+		c[1].appendInt32(int32(record.AgentID))
+		c[2].appendDouble(record.Age)
+		c[3].appendString(record.Gender)
+		c[4].appendBool(record.Alive)
+		c[5].appendInt64(record.Money)
+		c[6].appendString(record.Location)
+		c[7].appendString(record.BuildingType)
+		c[8].appendString(record.JobStatus)
+		c[9].appendString(record.MaritalStatus)
+		if record.HasGeo {
+			c[10].appendDouble(record.Lat)
+			c[11].appendDouble(record.Lon)
+		} else {
+			c[10].appendNull()
+			c[11].appendNull()
+		}
+		s.rows++
+	}
+
+	if s.rows >= parquetRowGroupSize {
+		return s.flushRowGroup()
+	}
+	return nil
+}
+
+// Close записывает оставшиеся строки и метаданные файла
+func (s *ParquetSink) Close() error {
+	err := s.flushRowGroup()
+	if err == nil {
+		metadata := s.fileMetadata()
+		footer := make([]byte, 4)
+		binary.LittleEndian.PutUint32(footer, uint32(len(metadata)))
+
+		err = errors.Join(s.write(metadata), s.write(footer), s.write([]byte(parquetMagic)), s.writer.Flush())
+	}
+
+	if closeErr := s.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// write пишет байты в файл, отслеживая смещение
+func (s *ParquetSink) write(data []byte) error {
+	n, err := s.writer.Write(data)
+	s.offset += int64(n)
+	return err
+}
+
+// flushRowGroup записывает накопленные строки как одну группу строк
+func (s *ParquetSink) flushRowGroup() error {
+	if s.rows == 0 {
+		return nil
+	}
+
+	group := parquetRowGroup{numRows: s.rows}
+	for _, column := range s.columns {
+		body := column.pageBody()
+
+		var header thriftWriter
+		header.beginStruct()
+		header.i32Field(1, parquetDataPage)
+		header.i32Field(2, int32(len(body)))
+		header.i32Field(3, int32(len(body)))
+		header.beginStructField(5) // DataPageHeader
+		header.i32Field(1, int32(s.rows))
+		header.i32Field(2, parquetEncodingPlain)
+		header.i32Field(3, parquetEncodingRLE)
+		header.i32Field(4, parquetEncodingRLE)
+		header.endStruct()
+		header.endStruct()
+
+		chunk := parquetChunk{
+			offset:    s.offset,
+			size:      int64(header.buf.Len() + len(body)),
+			numValues: s.rows,
+		}
+		if err := s.write(header.buf.Bytes()); err != nil {
+			return err
+		}
+		if err := s.write(body); err != nil {
+			return err
+		}
+
+		group.chunks = append(group.chunks, chunk)
+		group.size += chunk.size
+		column.reset()
+	}
+
+	s.rowGroups = append(s.rowGroups, group)
+	s.totalRows += s.rows
+	s.rows = 0
+	return nil
+}
+
+// fileMetadata кодирует FileMetaData: схему и положение всех групп строк
+func (s *ParquetSink) fileMetadata() []byte {
+	var t thriftWriter
+	t.beginStruct()
+	t.i32Field(1, 1) // версия формата
+
+	// Схема: корневой элемент и плоский список колонок
+	t.listField(2, thriftStruct, len(s.columns)+1)
+	t.beginStruct()
+	t.stringField(4, "schema")
+	t.i32Field(5, int32(len(s.columns)))
+	t.endStruct()
+	for _, column := range s.columns {
+		repetition := parquetRequired
+		if column.optional {
+			repetition = parquetOptional
+		}
+		t.beginStruct()
+		t.i32Field(1, column.kind)
+		t.i32Field(3, repetition)
+		t.stringField(4, column.name)
+		if column.utf8 {
+			t.i32Field(6, parquetConvertedUTF8)
+		}
+		t.endStruct()
+	}
+
+	t.i64Field(3, s.totalRows)
+
+	t.listField(4, thriftStruct, len(s.rowGroups))
+	for _, group := range s.rowGroups {
+		t.beginStruct()
+		t.listField(1, thriftStruct, len(group.chunks))
+		for i, chunk := range group.chunks {
+			column := s.columns[i]
+			t.beginStruct()
+			t.i64Field(2, chunk.offset)
+			t.beginStructField(3) // ColumnMetaData
+			t.i32Field(1, column.kind)
+			t.listField(2, thriftI32, 2)
+			t.i32(parquetEncodingPlain)
+			t.i32(parquetEncodingRLE)
+			t.listField(3, thriftBinary, 1)
+			t.binary([]byte(column.name))
+			t.i32Field(4, parquetUncompressed)
+			t.i64Field(5, chunk.numValues)
+			t.i64Field(6, chunk.size)
+			t.i64Field(7, chunk.size)
+			t.i64Field(9, chunk.offset)
+			t.endStruct()
+			t.endStruct()
+		}
+		t.i64Field(2, group.size)
+		t.i64Field(3, group.numRows)
+		t.endStruct()
+	}
+
+	t.stringField(6, "humanity")
+	t.endStruct()
+	return t.buf.Bytes()
+}
+
+func (c *parquetColumn) appendInt32(value int32) {
+	c.markDefined()
+	binary.Write(&c.values, binary.LittleEndian, value)
+}
+
+func (c *parquetColumn) appendInt64(value int64) {
+	c.markDefined()
+	binary.Write(&c.values, binary.LittleEndian, value)
+}
+
+func (c *parquetColumn) appendDouble(value float64) {
+	c.markDefined()
+	binary.Write(&c.values, binary.LittleEndian, math.Float64bits(value))
+}
+
+func (c *parquetColumn) appendString(value string) {
+	c.markDefined()
+	binary.Write(&c.values, binary.LittleEndian, uint32(len(value)))
+	c.values.WriteString(value)
+}
+
+func (c *parquetColumn) appendBool(value bool) {
+	c.markDefined()
+	c.bools = append(c.bools, value)
+}
+
+// appendNull добавляет пустое значение (только для optional колонок)
+func (c *parquetColumn) appendNull() {
+	c.defined = append(c.defined, false)
+}
+
+func (c *parquetColumn) markDefined() {
+	if c.optional {
+		c.defined = append(c.defined, true)
+	}
+}
+
+func (c *parquetColumn) reset() {
+	c.values.Reset()
+	c.bools = c.bools[:0]
+	c.defined = c.defined[:0]
+}
+
+// pageBody возвращает содержимое страницы данных: уровни определения и значения
+func (c *parquetColumn) pageBody() []byte {
+	var body bytes.Buffer
+
+	// Уровни определения (ширина 1 бит) в гибридной кодировке RLE с префиксом длины
+	if c.optional {
+		levels := encodeRLELevels(c.defined)
+		binary.Write(&body, binary.LittleEndian, uint32(len(levels)))
+		body.Write(levels)
+	}
+
+	if c.kind == parquetBoolean {
+		// PLAIN для BOOLEAN - по одному биту на значение, младшие биты первыми
+		packed := make([]byte, (len(c.bools)+7)/8)
+		for i, value := range c.bools {
+			if value {
+				packed[i/8] |= 1 << (i % 8)
+			}
+		}
+		body.Write(packed)
+	} else {
+		body.Write(c.values.Bytes())
+	}
+	return body.Bytes()
+}
+
+// encodeRLELevels кодирует уровни 0/1 сериями RLE: заголовок (длина << 1) и значение в одном байте
+func encodeRLELevels(levels []bool) []byte {
+	var out []byte
+	for i := 0; i < len(levels); {
+		j := i
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		out = binary.AppendUvarint(out, uint64(j-i)<<1)
+		if levels[i] {
+			out = append(out, 1)
+		} else {
+			out = append(out, 0)
+		}
+		i = j
+	}
+	return out
+}
+
+// Типы компактного протокола Thrift
+const (
+	thriftI32    byte = 5
+	thriftI64    byte = 6
+	thriftBinary byte = 8
+	thriftList   byte = 9
+	thriftStruct byte = 12
+)
+
+// thriftWriter кодирует структуры в компактном протоколе Thrift
+type thriftWriter struct {
+	buf       bytes.Buffer
+	lastField []int16 // номер последнего поля для каждой открытой структуры
+}
+
+func (t *thriftWriter) beginStruct() {
+	t.lastField = append(t.lastField, 0)
+}
+
+func (t *thriftWriter) beginStructField(id int16) {
+	t.fieldHeader(id, thriftStruct)
+	t.beginStruct()
+}
+
+func (t *thriftWriter) endStruct() {
+	t.buf.WriteByte(0) // STOP
+	t.lastField = t.lastField[:len(t.lastField)-1]
+}
+
+// fieldHeader пишет заголовок поля: разницу номеров и тип в одном байте, если возможно
+func (t *thriftWriter) fieldHeader(id int16, kind byte) {
+	last := &t.lastField[len(t.lastField)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | kind)
+	} else {
+		t.buf.WriteByte(kind)
+		t.varint(int64(id))
+	}
+	*last = id
+}
+
+func (t *thriftWriter) i32Field(id int16, value int32) {
+	t.fieldHeader(id, thriftI32)
+	t.i32(value)
+}
+
+func (t *thriftWriter) i64Field(id int16, value int64) {
+	t.fieldHeader(id, thriftI64)
+	t.varint(value)
+}
+
+func (t *thriftWriter) stringField(id int16, value string) {
+	t.fieldHeader(id, thriftBinary)
+	t.binary([]byte(value))
+}
+
+// listField пишет заголовок поля-списка; элементы пишутся следом без заголовков
+func (t *thriftWriter) listField(id int16, elemKind byte, size int) {
+	t.fieldHeader(id, thriftList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elemKind)
+	} else {
+		t.buf.WriteByte(0xF0 | elemKind)
+		t.uvarint(uint64(size))
+	}
+}
+
+func (t *thriftWriter) i32(value int32) {
+	t.varint(int64(value))
+}
+
+func (t *thriftWriter) binary(value []byte) {
+	t.uvarint(uint64(len(value)))
+	t.buf.Write(value)
+}
+
+// varint пишет целое в кодировке zigzag
+func (t *thriftWriter) varint(value int64) {
+	t.uvarint(uint64(value<<1) ^ uint64(value>>63))
+}
+
+func (t *thriftWriter) uvarint(value uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	t.buf.Write(tmp[:binary.PutUvarint(tmp[:], value)])
+}
@@ -2,6 +2,7 @@ package src
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/fallra1n/humanity/src/components"
 	"github.com/fallra1n/humanity/src/config"
@@ -10,23 +11,26 @@ import (
 
 // PopulationStats содержит статистику созданной популяции
 type PopulationStats struct {
-	TotalPeople         int
-	SmallCityEmployed   int
-	LargeCityEmployed   int
-	TotalEmployed       int
-	SmallCityPopulation int
-	LargeCityPopulation int
+	TotalPeople   int
+	TotalEmployed int
+	Cities        []CityPopulationStats
+}
+
+// CityPopulationStats содержит статистику популяции одного города
+type CityPopulationStats struct {
+	Name       string
+	Population int
+	Employed   int
 }
 
 // CreateCityPopulation создает популяцию для одного города
-func CreateCityPopulation(city *components.Location, targetPopulation int, globalTargets []*components.GlobalTarget) ([]*components.Human, int) {
+func CreateCityPopulation(city *components.Location, targetPopulation int, globalTargets []*components.GlobalTarget, rng *utils.Random) ([]*components.Human, int) {
 	var people []*components.Human
 	employedCount := int(float64(targetPopulation) * config.EmploymentRate)
 	residentialBuildings := components.GetResidentialBuildings(city)
 
 	for i := 0; i < targetPopulation; i++ {
 		human := components.NewHuman(make(map[*components.Human]bool), city, globalTargets)
-		human.Money = config.StartingMoney
 
 		// Назначить жилое здание
 		assigned := false
@@ -39,11 +43,12 @@ func CreateCityPopulation(city *components.Location, targetPopulation int, globa
 		if !assigned {
 			fmt.Printf("Warning: Could not assign residential building to %s human %d\n", city.Name, i+1)
 		}
+		components.NewHousehold(human)
 
-		// Трудоустройство на основе конфигурационного коэффициента
+		// Трудоустройство на основе конфигурационного коэффициента (дети не работают)
 		actualEmployed := 0
-		if i < employedCount {
-			if assignJob(human, city) {
+		if i < employedCount && human.Age >= config.AdultAge {
+			if assignJob(human, city, rng) {
 				actualEmployed++
 			}
 		}
@@ -64,7 +69,7 @@ func CreateCityPopulation(city *components.Location, targetPopulation int, globa
 }
 
 // assignJob назначает работу человеку в городе
-func assignJob(human *components.Human, city *components.Location) bool {
+func assignJob(human *components.Human, city *components.Location, rng *utils.Random) bool {
 	var availableVacancies []*components.Vacancy
 
 	// Искать работы в рабочих зданиях
@@ -90,9 +95,18 @@ func assignJob(human *components.Human, city *components.Location) bool {
 	}
 
 	if len(availableVacancies) > 0 {
-		chosenVacancy := availableVacancies[utils.GlobalRandom.NextInt(len(availableVacancies))]
+		// Упорядочить вакансии, чтобы выбор зависел только от зерна
+		sort.Slice(availableVacancies, func(i, j int) bool {
+			bi, bj := availableVacancies[i].Parent.Building, availableVacancies[j].Parent.Building
+			if bi != bj {
+				return bi.ID < bj.ID
+			}
+			return availableVacancies[i].ID < availableVacancies[j].ID
+		})
+
+		chosenVacancy := availableVacancies[rng.NextInt(len(availableVacancies))]
 		human.Job = chosenVacancy
-		human.JobTime = uint64(utils.GlobalRandom.NextInt(config.MaxInitialWorkExperience))
+		human.JobTime = uint64(rng.NextInt(config.MaxInitialWorkExperience))
 		human.WorkBuilding = chosenVacancy.Parent.Building // Установить рабочее здание
 		chosenVacancy.Parent.VacantPlaces[chosenVacancy]--
 		return true
@@ -101,41 +115,54 @@ func assignJob(human *components.Human, city *components.Location) bool {
 	return false
 }
 
-// CreatePopulation создает всю популяцию для симуляции
-func CreatePopulation(smallCity, largeCity *components.Location, globalTargets []*components.GlobalTarget) ([]*components.Human, PopulationStats) {
+// CreatePopulation создает всю популяцию для симуляции.
+// Население каждого города берется из его описания в config.Cities (по имени города)
+func CreatePopulation(cities []*components.Location, globalTargets []*components.GlobalTarget, rng *utils.Random) ([]*components.Human, PopulationStats) {
 	var allPeople []*components.Human
-
-	// Создать население малого города
-	smallCityPeople, smallCityEmployed := CreateCityPopulation(smallCity, config.SmallCityPopulation, globalTargets)
-	allPeople = append(allPeople, smallCityPeople...)
-
-	// Создать население большого города
-	largeCityPeople, largeCityEmployed := CreateCityPopulation(largeCity, config.LargeCityPopulation, globalTargets)
-	allPeople = append(allPeople, largeCityPeople...)
-
-	// Подсчитать общую статистику
-	stats := PopulationStats{
-		TotalPeople:         len(allPeople),
-		SmallCityEmployed:   smallCityEmployed,
-		LargeCityEmployed:   largeCityEmployed,
-		TotalEmployed:       smallCityEmployed + largeCityEmployed,
-		SmallCityPopulation: config.SmallCityPopulation,
-		LargeCityPopulation: config.LargeCityPopulation,
+	stats := PopulationStats{}
+
+	for _, city := range cities {
+		population := cityPopulation(city.Name)
+		cityPeople, employed := CreateCityPopulation(city, population, globalTargets, rng)
+		allPeople = append(allPeople, cityPeople...)
+
+		stats.TotalEmployed += employed
+		stats.Cities = append(stats.Cities, CityPopulationStats{
+			Name:       city.Name,
+			Population: population,
+			Employed:   employed,
+		})
 	}
+	stats.TotalPeople = len(allPeople)
 
 	return allPeople, stats
 }
 
+// cityPopulation возвращает население города из его описания в config.Cities
+func cityPopulation(name string) int {
+	for _, spec := range config.Cities {
+		if spec.Name == name {
+			return spec.Population
+		}
+	}
+	return 0
+}
+
 // PrintPopulationStats выводит статистику созданной популяции
-func PrintPopulationStats(stats PopulationStats, smallCity, largeCity *components.Location) {
+func PrintPopulationStats(stats PopulationStats) {
 	fmt.Printf("\nCreated %d people total:\n", stats.TotalPeople)
-	fmt.Printf("  %s: %d residents, %d employed (%.1f%%)\n",
-		smallCity.Name, stats.SmallCityPopulation, stats.SmallCityEmployed,
-		float64(stats.SmallCityEmployed)/float64(stats.SmallCityPopulation)*100)
-	fmt.Printf("  %s: %d residents, %d employed (%.1f%%)\n",
-		largeCity.Name, stats.LargeCityPopulation, stats.LargeCityEmployed,
-		float64(stats.LargeCityEmployed)/float64(stats.LargeCityPopulation)*100)
+	for _, city := range stats.Cities {
+		fmt.Printf("  %s: %d residents, %d employed (%.1f%%)\n",
+			city.Name, city.Population, city.Employed, percent(city.Employed, city.Population))
+	}
 	fmt.Printf("Total employment: %d employed, %d unemployed (%.1f%% employment rate)\n",
-		stats.TotalEmployed, stats.TotalPeople-stats.TotalEmployed,
-		float64(stats.TotalEmployed)/float64(stats.TotalPeople)*100)
+		stats.TotalEmployed, stats.TotalPeople-stats.TotalEmployed, percent(stats.TotalEmployed, stats.TotalPeople))
+}
+
+// percent возвращает долю part от total в процентах (0 для пустого total)
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
 }
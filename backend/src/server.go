@@ -0,0 +1,473 @@
+package src
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fallra1n/humanity/src/components"
+	"github.com/fallra1n/humanity/src/utils"
+)
+
+// HTTP API для управления симуляцией и просмотра ее состояния.
+//
+//	POST /api/start, /api/pause, /api/resume - управление ходом симуляции
+//	POST /api/step?hours=N                   - выполнить N часов (по умолчанию 1) и остановиться
+//	GET  /api/tick                           - текущий час и состояние симуляции
+//	GET  /api/stats                          - агрегированная статистика
+//	GET  /api/agents/{id}                    - человек по ID из GlobalHumanStorage
+//	GET  /api/buildings?city=Name            - заполненность зданий (всех городов или одного)
+//	GET  /api/cities/{name}/vacancies        - вакансии города
+//	GET  /ws                                 - WebSocket с положением людей после каждого часа (см. websocket.go)
+//
+// Обработчики читают состояние под s.stateMu, поэтому видят только границы часов
+
+// buildingInfo описывает здание (ID уникален в пределах города)
+type buildingInfo struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	City string `json:"city"`
+	Type string `json:"type"`
+}
+
+// jobView описывает работу человека
+type jobView struct {
+	Building buildingInfo `json:"building"`
+	Vacancy  int          `json:"vacancy"`
+	Salary   int          `json:"salary"`
+}
+
+// householdView описывает домохозяйство человека
+type householdView struct {
+	ID        int           `json:"id"`
+	Members   []int         `json:"members"`
+	Residence *buildingInfo `json:"residence"`
+	Budget    int64         `json:"budget"`
+	Income    int64         `json:"income"`   // за последний месяц
+	Expenses  int64         `json:"expenses"` // за последний месяц
+}
+
+// agentView - подробное состояние человека
+type agentView struct {
+	ID                     int              `json:"id"`
+	Age                    float64          `json:"age"`
+	Gender                 string           `json:"gender"`
+	LifeStage              string           `json:"life_stage"`
+	Alive                  bool             `json:"alive"`
+	DeathCause             string           `json:"death_cause,omitempty"`
+	Money                  int64            `json:"money"`
+	MaritalStatus          string           `json:"marital_status"`
+	DivorceCount           int              `json:"divorce_count"`
+	Pregnant               bool             `json:"pregnant"`
+	Health                 float64          `json:"health"`
+	Illness                string           `json:"illness,omitempty"`
+	SickLeaveHours         uint64           `json:"sick_leave_hours"`
+	Hospital               *buildingInfo    `json:"hospital"`
+	City                   string           `json:"city"`
+	CurrentBuilding        *buildingInfo    `json:"current_building"`
+	ResidentialBuilding    *buildingInfo    `json:"residential_building"`
+	Job                    *jobView         `json:"job"`
+	JobTime                uint64           `json:"job_time"`
+	UnemployedTime         uint64           `json:"unemployed_time"`
+	BusyHours              uint64           `json:"busy_hours"`
+	Activity               *string          `json:"activity"`
+	Spouse                 *int             `json:"spouse"`
+	Parents                []int            `json:"parents"`
+	Children               []int            `json:"children"`
+	Friends                []int            `json:"friends"`
+	GlobalTargets          []string         `json:"global_targets"`
+	CompletedGlobalTargets []string         `json:"completed_global_targets"`
+	Splashes               []string         `json:"splashes"`
+	Items                  map[string]int64 `json:"items"`
+	Household              *householdView   `json:"household"`
+}
+
+// buildingOccupancy - заполненность здания
+type buildingOccupancy struct {
+	buildingInfo
+	Capacity  int `json:"capacity"`
+	Occupied  int `json:"occupied"`  // занятые квартиры или места
+	Residents int `json:"residents"` // живущие в здании (вместе с семьями владельцев)
+	Present   int `json:"present"`   // находятся в здании сейчас
+	Workers   int `json:"workers"`   // работают в здании
+}
+
+// vacancyRow - строка таблицы вакансий города
+type vacancyRow struct {
+	Building     buildingInfo `json:"building"`
+	Vacancy      int          `json:"vacancy"`
+	Salary       int          `json:"salary"`
+	Free         uint64       `json:"free"`     // свободные места
+	Employed     int          `json:"employed"` // занятые места
+	RequiredTags []string     `json:"required_tags"`
+}
+
+// startServer запускает HTTP API на адресе s.ServeAddr
+func (s *Simulation) startServer() (*http.Server, error) {
+	listener, err := net.Listen("tcp", s.ServeAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %v", s.ServeAddr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/start", s.handleControl(s.control.Start))
+	mux.HandleFunc("/api/pause", s.handleControl(s.control.Pause))
+	mux.HandleFunc("/api/resume", s.handleControl(s.control.Resume))
+	mux.HandleFunc("/api/step", s.handleStep)
+	mux.HandleFunc("/api/tick", s.handleTick)
+	mux.HandleFunc("/api/stats", s.handleStats)
+	mux.HandleFunc("/api/agents/", s.handleAgent)
+	mux.HandleFunc("/api/buildings", s.handleBuildings)
+	mux.HandleFunc("/api/cities/", s.handleVacancies)
+
+	s.stream = NewPositionStream()
+	mux.Handle("/ws", s.stream)
+
+	server := &http.Server{Handler: mux}
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("Warning: HTTP server stopped: %v", err)
+		}
+	}()
+
+	fmt.Printf("HTTP API listening on %s\n", listener.Addr())
+	return server, nil
+}
+
+// handleControl оборачивает команду контроллера в POST обработчик
+func (s *Simulation) handleControl(command func() error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !requireMethod(w, r, http.MethodPost) {
+			return
+		}
+		if err := command(); err != nil {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+		s.handleTick(w, r)
+	}
+}
+
+func (s *Simulation) handleStep(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	hours := uint64(1)
+	if value := r.URL.Query().Get("hours"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid hours %q", value))
+			return
+		}
+		hours = parsed
+	}
+
+	if err := s.control.Step(hours); err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	s.handleTick(w, r)
+}
+
+func (s *Simulation) handleTick(w http.ResponseWriter, r *http.Request) {
+	s.stateMu.RLock()
+	tick := utils.GlobalTick.Get()
+	s.stateMu.RUnlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"tick":     tick,
+		"duration": s.Duration,
+		"state":    s.control.State(),
+	})
+}
+
+func (s *Simulation) handleStats(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	s.stateMu.RLock()
+	stats := CalculateStatistics(s.everyone(), s.Cities)
+	s.stateMu.RUnlock()
+
+	writeJSON(w, http.StatusOK, stats)
+}
+
+func (s *Simulation) handleAgent(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	value := strings.TrimPrefix(r.URL.Path, "/api/agents/")
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid agent id %q", value))
+		return
+	}
+
+	s.stateMu.RLock()
+	defer s.stateMu.RUnlock()
+
+	for _, person := range s.everyone() {
+		if components.GlobalHumanStorage.Get(person) == id {
+			writeJSON(w, http.StatusOK, newAgentView(person))
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Errorf("agent %d not found", id))
+}
+
+func (s *Simulation) handleBuildings(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	s.stateMu.RLock()
+	defer s.stateMu.RUnlock()
+
+	cities := s.Cities
+	if name := r.URL.Query().Get("city"); name != "" {
+		city := s.findCity(name)
+		if city == nil {
+			writeError(w, http.StatusNotFound, fmt.Errorf("city %q not found", name))
+			return
+		}
+		cities = []*components.Location{city}
+	}
+
+	// Кто где находится и работает
+	present := make(map[*components.Building]int)
+	workers := make(map[*components.Building]int)
+	for _, person := range s.people {
+		if person.Dead {
+			continue
+		}
+		if person.CurrentBuilding != nil {
+			present[person.CurrentBuilding]++
+		}
+		if person.Job != nil {
+			workers[person.Job.Parent.Building]++
+		}
+	}
+
+	rows := make([]buildingOccupancy, 0)
+	for _, city := range cities {
+		for _, building := range components.GetBuildings(city) {
+			building.Mu.RLock()
+			rows = append(rows, buildingOccupancy{
+				buildingInfo: newBuildingInfo(building),
+				Capacity:     building.Capacity,
+				Occupied:     building.Occupied,
+				Residents:    len(building.Residents),
+				Present:      present[building],
+				Workers:      workers[building],
+			})
+			building.Mu.RUnlock()
+		}
+	}
+	writeJSON(w, http.StatusOK, rows)
+}
+
+func (s *Simulation) handleVacancies(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	name, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/api/cities/"), "/vacancies")
+	if !ok || name == "" {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown path %s", r.URL.Path))
+		return
+	}
+
+	s.stateMu.RLock()
+	defer s.stateMu.RUnlock()
+
+	city := s.findCity(name)
+	if city == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("city %q not found", name))
+		return
+	}
+
+	employed := make(map[*components.Vacancy]int)
+	for _, person := range s.people {
+		if !person.Dead && person.Job != nil {
+			employed[person.Job]++
+		}
+	}
+
+	rows := make([]vacancyRow, 0)
+	for _, building := range components.GetWorkplaceBuildings(city) {
+		var vacancies []*components.Vacancy
+		free := make(map[*components.Vacancy]uint64)
+
+		building.Mu.RLock()
+		for job := range building.Jobs {
+			job.Mu.RLock()
+			for vacancy, count := range job.VacantPlaces {
+				vacancies = append(vacancies, vacancy)
+				free[vacancy] = count
+			}
+			job.Mu.RUnlock()
+		}
+		building.Mu.RUnlock()
+
+		sort.Slice(vacancies, func(i, j int) bool { return vacancies[i].ID < vacancies[j].ID })
+		for _, vacancy := range vacancies {
+			rows = append(rows, vacancyRow{
+				Building:     newBuildingInfo(building),
+				Vacancy:      vacancy.ID,
+				Salary:       vacancy.Payment,
+				Free:         free[vacancy],
+				Employed:     employed[vacancy],
+				RequiredTags: sortedKeys(vacancy.RequiredTags),
+			})
+		}
+	}
+	writeJSON(w, http.StatusOK, rows)
+}
+
+// findCity ищет город по имени
+func (s *Simulation) findCity(name string) *components.Location {
+	for _, city := range s.Cities {
+		if city.Name == name {
+			return city
+		}
+	}
+	return nil
+}
+
+// newAgentView собирает подробное состояние человека
+func newAgentView(person *components.Human) agentView {
+	view := agentView{
+		ID:                     components.GlobalHumanStorage.Get(person),
+		Age:                    person.Age,
+		Gender:                 string(person.Gender),
+		Alive:                  !person.Dead,
+		DeathCause:             string(person.DeathCause),
+		Money:                  person.Money,
+		MaritalStatus:          string(person.MaritalStatus),
+		LifeStage:              string(person.LifeStage()),
+		DivorceCount:           person.DivorceCount,
+		Pregnant:               person.IsPregnant,
+		Health:                 person.Health,
+		Illness:                person.Illness,
+		SickLeaveHours:         person.SickLeaveHours,
+		JobTime:                person.JobTime,
+		UnemployedTime:         person.UnemployedTime,
+		BusyHours:              person.BusyHours,
+		Parents:                relatedIDs(person.Parents),
+		Children:               relatedIDs(person.Children),
+		Friends:                relatedIDs(person.Friends),
+		GlobalTargets:          make([]string, 0, len(person.GlobalTargets)),
+		CompletedGlobalTargets: make([]string, 0, len(person.CompletedGlobalTargets)),
+		Splashes:               make([]string, 0, len(person.Splashes)),
+		Items:                  person.Items,
+	}
+
+	if person.HomeLocation != nil {
+		view.City = person.HomeLocation.Name
+	}
+	if person.CurrentBuilding != nil {
+		ref := newBuildingInfo(person.CurrentBuilding)
+		view.CurrentBuilding = &ref
+	}
+	if person.ResidentialBuilding != nil {
+		ref := newBuildingInfo(person.ResidentialBuilding)
+		view.ResidentialBuilding = &ref
+	}
+	if person.Hospital != nil {
+		ref := newBuildingInfo(person.Hospital)
+		view.Hospital = &ref
+	}
+	if person.Job != nil {
+		view.Job = &jobView{
+			Building: newBuildingInfo(person.Job.Parent.Building),
+			Vacancy:  person.Job.ID,
+			Salary:   person.Job.Payment,
+		}
+	}
+	if person.Activity != nil {
+		view.Activity = &person.Activity.Name
+	}
+	if person.Spouse != nil {
+		id := components.GlobalHumanStorage.Get(person.Spouse)
+		view.Spouse = &id
+	}
+	if household := person.Household; household != nil {
+		view.Household = &householdView{
+			ID:       household.ID,
+			Members:  make([]int, 0, len(household.Members)),
+			Budget:   household.Budget,
+			Income:   household.Income,
+			Expenses: household.Expenses,
+		}
+		for _, member := range household.SortedMembers() {
+			view.Household.Members = append(view.Household.Members, components.GlobalHumanStorage.Get(member))
+		}
+		if household.Residence != nil {
+			ref := newBuildingInfo(household.Residence)
+			view.Household.Residence = &ref
+		}
+	}
+
+	for target := range person.GlobalTargets {
+		view.GlobalTargets = append(view.GlobalTargets, target.Name)
+	}
+	sort.Strings(view.GlobalTargets)
+	for target := range person.CompletedGlobalTargets {
+		view.CompletedGlobalTargets = append(view.CompletedGlobalTargets, target.Name)
+	}
+	sort.Strings(view.CompletedGlobalTargets)
+	for _, splash := range person.Splashes {
+		view.Splashes = append(view.Splashes, splash.Name)
+	}
+
+	return view
+}
+
+func newBuildingInfo(building *components.Building) buildingInfo {
+	return buildingInfo{
+		ID:   building.ID,
+		Name: building.Name,
+		City: building.Location.Name,
+		Type: string(building.Type),
+	}
+}
+
+// humanIDs возвращает упорядоченные ID людей из карты отношений
+func relatedIDs(humans map[*components.Human]float64) []int {
+	ids := make([]int, 0, len(humans))
+	for human := range humans {
+		ids = append(ids, components.GlobalHumanStorage.Get(human))
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// requireMethod отвечает 405, если запрос сделан не тем методом
+func requireMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		log.Printf("Warning: Failed to write HTTP response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
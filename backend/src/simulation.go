@@ -3,7 +3,11 @@ package src
 import (
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"sort"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/fallra1n/humanity/src/components"
@@ -16,14 +20,35 @@ type Simulation struct {
 	AgentCount int    // количество агентов
 	Duration   uint64 // длительность симуляции в часах
 	ShowStats  bool   // показывать ли подробную статистику
+	Seed       int64  // зерно генератора случайных чисел (0 - выбрать по времени)
+
+	ScenarioPath string // файл сценария (пусто - параметры по умолчанию)
+
+	CheckpointPath  string // файл для периодических контрольных точек
+	CheckpointEvery uint64 // период сохранения контрольных точек в часах (0 - не сохранять)
+	ResumePath      string // контрольная точка, с которой нужно продолжить симуляцию
+
+	EventsPath string // файл журнала событий JSONL (пусто - не записывать)
+
+	Outputs      []OutputSpec // приемники состояния людей (пусто - не записывать)
+	OutputAppend bool         // дописывать в существующие файлы вместо перезаписи
+	OutputEvery  uint64       // записывать состояние каждые N часов (0 и 1 - каждый час)
+
+	ServeAddr string // адрес HTTP API (пусто - симуляция идет без управления извне)
 
 	// Internal fields
 	actions       []*components.Action
 	localTargets  []*components.LocalTarget
 	globalTargets []*components.GlobalTarget
-	people        []*components.Human
-	smallCity     *components.Location
-	largeCity     *components.Location
+	people        []*components.Human // живые люди
+	archive       []*components.Human // умершие, в порядке смерти
+	outputs       []OutputSink
+
+	control *Controller     // управление ходом симуляции через HTTP API (nil - без управления)
+	stateMu sync.RWMutex    // защищает состояние мира от чтения обработчиками HTTP посреди часа
+	stream  *PositionStream // трансляция положения людей по WebSocket (nil - без HTTP API)
+
+	Cities []*components.Location // города симуляции
 }
 
 // NewSimulation creates a new simulation instance
@@ -38,60 +63,113 @@ func NewSimulation(agentCount int, duration uint64, showStats bool) *Simulation
 // NewDefaultSimulation creates a simulation with default parameters from config
 func NewDefaultSimulation(showStats bool) *Simulation {
 	return &Simulation{
-		AgentCount: config.TotalPopulation,
-		Duration:   config.TotalSimulationHours,
-		ShowStats:  showStats,
+		AgentCount:  config.TotalPopulation,
+		Duration:    config.TotalSimulationHours,
+		ShowStats:   showStats,
+		Outputs:     []OutputSpec{{Format: OutputCSV, Path: "log.csv"}},
+		OutputEvery: 1,
+	}
+}
+
+// loadScenario loads the scenario file and makes it the current configuration
+func (s *Simulation) loadScenario() error {
+	if s.ScenarioPath == "" {
+		return nil
 	}
+
+	scenario, err := config.LoadScenario(s.ScenarioPath)
+	if err != nil {
+		return err
+	}
+	scenario.Apply()
+
+	// Параметры сценария имеют приоритет над значениями, заданными при создании
+	s.AgentCount = config.TotalPopulation
+	s.Duration = config.TotalSimulationHours
+
+	return nil
 }
 
-// loadInitData loads actions, local targets, and global targets from configuration files
+// loadInitData loads actions, local targets, global targets, and splashes from configuration files
 func (s *Simulation) loadInitData() error {
 	// Загрузить действия
-	actions, err := LoadActions("actions.ini")
+	actions, err := LoadActions(config.ActionsFile)
 	if err != nil {
 		return fmt.Errorf("failed to load actions: %v", err)
 	}
 	s.actions = actions
 
 	// Загрузить локальные цели
-	localTargets, err := LoadLocalTargets("local.ini", actions)
+	localTargets, err := LoadLocalTargets(config.LocalTargetsFile, actions)
 	if err != nil {
 		return fmt.Errorf("failed to load local targets: %v", err)
 	}
 	s.localTargets = localTargets
 
 	// Загрузить глобальные цели
-	globalTargets, err := LoadGlobalTargets("global.ini", localTargets)
+	globalTargets, err := LoadGlobalTargets(config.GlobalTargetsFile, localTargets)
 	if err != nil {
 		return fmt.Errorf("failed to load global targets: %v", err)
 	}
 	s.globalTargets = globalTargets
 
+	// Модификаторы смертности ссылаются на глобальные цели по имени
+	known := make(map[string]bool)
+	for _, target := range globalTargets {
+		known[target.Name] = true
+	}
+	for _, modifier := range config.MortalityModifiers {
+		if modifier.Target != "" && !known[modifier.Target] {
+			log.Printf("Warning: mortality modifier refers to unknown global target %s", modifier.Target)
+		}
+	}
+
+	// Загрузить описания всплесков
+	splashes, err := LoadSplashes(config.SplashesFile)
+	if err != nil {
+		return fmt.Errorf("failed to load splashes: %v", err)
+	}
+	if err := components.SetSplashDefinitions(splashes); err != nil {
+		return fmt.Errorf("invalid splashes in %s: %v", config.SplashesFile, err)
+	}
+
 	return nil
 }
 
+// initializeRandom seeds the global random source; per-agent streams are derived from it
+func (s *Simulation) initializeRandom() {
+	if s.Seed == 0 {
+		s.Seed = time.Now().UnixNano()
+	}
+	utils.SetGlobalSeed(s.Seed)
+
+	// Вывести зерно, чтобы прогон можно было воспроизвести
+	fmt.Printf("Random seed: %d\n", s.Seed)
+}
+
 // initializeCities creates and initializes the cities for the simulation
 func (s *Simulation) initializeCities() {
-	// Создать два города
-	s.smallCity = components.CreateSmallCity("City 1")
-	s.largeCity = components.CreateLargeCity("City 2")
+	// Создать города по описаниям из конфигурации
+	s.Cities = components.BuildCities(config.Cities, utils.GlobalRandom)
+	components.BuildPaths(s.Cities, config.Paths)
 
 	// Вывести информацию о городах (только если включен флаг --stat)
 	if s.ShowStats {
-		components.PrintCityInfo(s.smallCity)
-		components.PrintCityInfo(s.largeCity)
+		for _, city := range s.Cities {
+			components.PrintCityInfo(city)
+		}
 	}
 }
 
 // initializePopulation creates the initial population for the simulation
 func (s *Simulation) initializePopulation() {
 	// Создать популяцию для симуляции
-	people, populationStats := CreatePopulation(s.smallCity, s.largeCity, s.globalTargets)
+	people, populationStats := CreatePopulation(s.Cities, s.globalTargets, utils.GlobalRandom)
 	s.people = people
 
 	// Вывести статистику популяции (только если включен флаг --stat)
 	if s.ShowStats {
-		PrintPopulationStats(populationStats, s.smallCity, s.largeCity)
+		PrintPopulationStats(populationStats)
 		PrintInitialStatistics(s.people)
 	}
 }
@@ -100,8 +178,14 @@ func (s *Simulation) initializePopulation() {
 func (s *Simulation) runSimulationLoop() error {
 	var iterateTimer time.Duration
 
-	// Основной цикл симуляции
-	for hour := uint64(0); hour < s.Duration; hour++ {
+	// Основной цикл симуляции (при возобновлении начинается с сохраненного часа)
+	for hour := utils.GlobalTick.Get(); hour < s.Duration; hour++ {
+		// Дождаться разрешения контроллера (пауза, пошаговый режим)
+		if s.control != nil {
+			s.control.waitTurn()
+		}
+
+		s.stateMu.Lock()
 		startTime := time.Now()
 
 		wg := sync.WaitGroup{}
@@ -120,11 +204,20 @@ func (s *Simulation) runSimulationLoop() error {
 
 		wg.Wait()
 
+		// Применить отложенные операции над общим состоянием в фиксированном порядке
+		for _, person := range s.people {
+			person.ApplySharedEffects()
+		}
+
+		// Расходы, зарплаты и долги домохозяйств
+		components.ProcessHouseholds(s.people)
+
 		// Обработать дружбу после того, как все люди действовали (однопоточно для безопасности)
 		// Только в нерабочие часы сна
 		if !utils.IsSleepTime(utils.GlobalTick.Get()) {
-			components.ProcessFriendships(s.people)
-			components.ProcessMarriages(s.people)
+			components.ProcessFriendships(s.people, utils.GlobalRandom)
+			components.ProcessMarriages(s.people, utils.GlobalRandom)
+			components.ProcessDivorces(s.people, utils.GlobalRandom)
 		}
 
 		// Обработать роды (дети, рожденные в течение этого часа)
@@ -133,6 +226,9 @@ func (s *Simulation) runSimulationLoop() error {
 			s.people = append(s.people, newChildren...)
 		}
 
+		// Пересмотреть глобальные цели: этап жизни, невыполнимые и выполненные цели
+		components.ReviewGoals(s.people, s.globalTargets)
+
 		// Обработать потенциальные увольнения после того, как все люди действовали
 		for _, person := range s.people {
 			if !person.Dead {
@@ -142,15 +238,33 @@ func (s *Simulation) runSimulationLoop() error {
 			}
 		}
 
-		// Записать текущее состояние в CSV
-		if err := logToCSV(s.people, hour); err != nil {
-			log.Printf("Warning: Failed to write to log.csv: %v", err)
-		}
+		// Обработать переезды между городами
+		components.ProcessMigrations(s.people)
+
+		// Записать текущее состояние в приемники вывода
+		s.writeOutputs(hour)
+
+		// Отправить положение людей клиентам WebSocket
+		s.streamPositions(hour)
+
+		// Умершие за час больше не обрабатываются и не попадают в вывод
+		s.archiveDead()
 
 		iterateTimer += time.Since(startTime)
 
 		// Увеличить глобальное время
 		utils.GlobalTick.Increment()
+
+		// Сохранить контрольную точку на границе часа
+		err := s.maybeCheckpoint()
+		s.stateMu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.control != nil {
+		s.control.finish()
 	}
 
 	fmt.Printf("Simulation completed. Total iteration time: %v\n", iterateTimer)
@@ -160,18 +274,52 @@ func (s *Simulation) runSimulationLoop() error {
 // printResults outputs the final simulation results
 func (s *Simulation) printResults() {
 	if s.ShowStats {
-		PrintFinalStatistics(s.people)
-		PrintSimulationSummary(s.people, s.smallCity, s.largeCity)
+		PrintFinalStatistics(s.everyone())
+		PrintSimulationSummary(s.everyone(), s.Cities)
 	} else {
 		// Краткая статистика без флага --stat
-		stats := CalculateStatistics(s.people, s.smallCity, s.largeCity)
+		everyone := s.everyone()
+		stats := CalculateStatistics(everyone, s.Cities)
 		fmt.Printf("Simulation completed. Population: %d (%d alive, %d employed)\n",
-			len(s.people), stats.AliveCount, stats.EmployedCount)
+			len(everyone), stats.AliveCount, stats.EmployedCount)
+	}
+}
+
+// archiveDead moves people who died during the hour from the live list to the archive.
+// Their last output row is the one written in the hour of death
+func (s *Simulation) archiveDead() {
+	alive := s.people[:0]
+	for _, person := range s.people {
+		if person.Dead {
+			s.archive = append(s.archive, person)
+		} else {
+			alive = append(alive, person)
+		}
 	}
+	s.people = alive
+}
+
+// everyone returns living and archived people ordered by ID, for statistics
+func (s *Simulation) everyone() []*components.Human {
+	all := make([]*components.Human, 0, len(s.people)+len(s.archive))
+	all = append(all, s.people...)
+	all = append(all, s.archive...)
+	sort.Slice(all, func(i, j int) bool {
+		return components.GlobalHumanStorage.Get(all[i]) < components.GlobalHumanStorage.Get(all[j])
+	})
+	return all
 }
 
 // Run executes the complete simulation
 func (s *Simulation) Run() error {
+	// Загрузить сценарий
+	if err := s.loadScenario(); err != nil {
+		return err
+	}
+	if err := components.SetPlanner(config.PlannerType); err != nil {
+		return err
+	}
+
 	// Загрузить начальные данные
 	if err := s.loadInitData(); err != nil {
 		return err
@@ -183,11 +331,54 @@ func (s *Simulation) Run() error {
 		return fmt.Errorf("failed to create name maps: %v", err)
 	}
 
-	// Инициализировать города
-	s.initializeCities()
+	if s.ResumePath != "" {
+		// Продолжить с контрольной точки
+		if err := s.LoadCheckpoint(s.ResumePath); err != nil {
+			return err
+		}
+		fmt.Printf("Resumed from %s at hour %d (seed %d)\n", s.ResumePath, utils.GlobalTick.Get(), s.Seed)
+	} else {
+		// Инициализировать генератор случайных чисел до создания городов и людей
+		s.initializeRandom()
+
+		// Инициализировать города
+		s.initializeCities()
+
+		// Инициализировать популяцию
+		s.initializePopulation()
+	}
+
+	// Открыть журнал событий
+	if s.EventsPath != "" {
+		events, err := NewEventLog(s.EventsPath)
+		if err != nil {
+			return err
+		}
+		components.SetEventRecorder(events)
+		defer func() {
+			components.SetEventRecorder(nil)
+			if err := events.Close(); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+		}()
+	}
 
-	// Инициализировать популяцию
-	s.initializePopulation()
+	// Открыть приемники состояния людей
+	if err := s.openOutputs(); err != nil {
+		return err
+	}
+	defer s.closeOutputs()
+
+	// Запустить HTTP API; симуляция ждет команды start
+	if s.ServeAddr != "" {
+		s.control = NewController()
+		server, err := s.startServer()
+		if err != nil {
+			return err
+		}
+		defer server.Close()
+		defer s.stream.Close()
+	}
 
 	// Запустить основной цикл симуляции
 	if err := s.runSimulationLoop(); err != nil {
@@ -197,6 +388,14 @@ func (s *Simulation) Run() error {
 	// Вывести результаты
 	s.printResults()
 
+	// Оставить HTTP API доступным для просмотра итогового состояния
+	if s.ServeAddr != "" {
+		fmt.Println("HTTP API is still serving the final state, press Ctrl+C to exit")
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+		<-interrupt
+	}
+
 	// Подавить предупреждения о неиспользуемых переменных
 	_ = actionMap
 	_ = localMap
@@ -4,41 +4,70 @@ import (
 	"fmt"
 
 	"github.com/fallra1n/humanity/src/components"
+	"github.com/fallra1n/humanity/src/config"
 )
 
 // SimulationStatistics содержит статистику симуляции
 type SimulationStatistics struct {
-	AliveCount                 int
-	CompletedTargetsCount      int
-	TotalMoney                 int64
-	EmployedCount              int
-	TotalItems                 int
-	MaleCount                  int
-	FemaleCount                int
-	MarriedCount               int
-	ChildrenCount              int
-	PregnantCount              int
-	TotalChildren              int
-	MoveCount                  int
-	ApartmentsForSaleLargeCity int
-	PeopleWithoutHousing       int
-	TotalFriends               int
-	PeopleWithFriends          int
-	PeopleAtWork               int
-	PeopleAtHome               int
-	TargetStats                map[string]int
+	AliveCount            int            `json:"alive_count"`
+	CompletedTargetsCount int            `json:"completed_targets_count"`
+	TotalMoney            int64          `json:"total_money"` // личные деньги и бюджеты домохозяйств
+	EmployedCount         int            `json:"employed_count"`
+	TotalItems            int            `json:"total_items"`
+	MaleCount             int            `json:"male_count"`
+	FemaleCount           int            `json:"female_count"`
+	MarriedCount          int            `json:"married_count"`
+	DivorcedCount         int            `json:"divorced_count"` // Сейчас в разводе
+	WidowedCount          int            `json:"widowed_count"`
+	DivorceCount          int            `json:"divorce_count"` // Всего разводов за симуляцию
+	ChildrenCount         int            `json:"children_count"`
+	PregnantCount         int            `json:"pregnant_count"`
+	TotalChildren         int            `json:"total_children"`
+	MoveCount             int            `json:"move_count"`
+	PeopleWithoutHousing  int            `json:"people_without_housing"`
+	TotalFriends          int            `json:"total_friends"`
+	PeopleWithFriends     int            `json:"people_with_friends"`
+	PeopleAtWork          int            `json:"people_at_work"`
+	PeopleAtHome          int            `json:"people_at_home"`
+	TargetStats           map[string]int `json:"target_stats"`
+	CityResidents         map[string]int `json:"city_residents"`
+	DeathCauses           map[string]int `json:"death_causes"`
+	SickCount             int            `json:"sick_count"`
+	HospitalizedCount     int            `json:"hospitalized_count"`
+	AverageHealth         float64        `json:"average_health"` // среди живых
+	HouseholdCount        int            `json:"household_count"`
+	AverageHouseholdSize  float64        `json:"average_household_size"`
+	HouseholdBudget       int64          `json:"household_budget"`   // сумма бюджетов
+	HouseholdIncome       int64          `json:"household_income"`   // сумма доходов за последний месяц
+	HouseholdExpenses     int64          `json:"household_expenses"` // сумма расходов за последний месяц
+	FamilyHouseholds      int            `json:"family_households"`  // с несовершеннолетними детьми
 }
 
 // CalculateStatistics вычисляет статистику симуляции
-func CalculateStatistics(people []*components.Human, smallCity, largeCity *components.Location) SimulationStatistics {
+func CalculateStatistics(people []*components.Human, cities []*components.Location) SimulationStatistics {
 	stats := SimulationStatistics{
-		TargetStats: make(map[string]int),
+		TargetStats:   make(map[string]int),
+		CityResidents: make(map[string]int),
+		DeathCauses:   make(map[string]int),
+	}
+
+	// Жители городов
+	for _, city := range cities {
+		city.Mu.RLock()
+		for human := range city.Humans {
+			if !human.Dead {
+				stats.CityResidents[city.Name]++
+			}
+		}
+		city.Mu.RUnlock()
 	}
 
 	// Основная статистика по людям
 	for _, person := range people {
 		if !person.Dead {
 			stats.AliveCount++
+		} else {
+			stats.DeathCauses[string(person.DeathCause)]++
 		}
 		if person.Job != nil {
 			stats.EmployedCount++
@@ -48,9 +77,17 @@ func CalculateStatistics(people []*components.Human, smallCity, largeCity *compo
 		} else {
 			stats.FemaleCount++
 		}
-		if person.MaritalStatus == components.Married {
-			stats.MarriedCount++
+		if !person.Dead {
+			switch person.MaritalStatus {
+			case components.Married:
+				stats.MarriedCount++
+			case components.Divorced:
+				stats.DivorcedCount++
+			case components.Widowed:
+				stats.WidowedCount++
+			}
 		}
+		stats.DivorceCount += person.DivorceCount
 		if person.Age < 18.0 {
 			stats.ChildrenCount++
 		}
@@ -62,8 +99,8 @@ func CalculateStatistics(people []*components.Human, smallCity, largeCity *compo
 		stats.TotalMoney += person.Money
 		stats.TotalItems += len(person.Items)
 
-		// Подсчитать людей без жилья
-		if person.ResidentialBuilding == nil {
+		// Подсчитать живых людей без жилья
+		if !person.Dead && person.ResidentialBuilding == nil {
 			stats.PeopleWithoutHousing++
 		}
 
@@ -77,8 +114,16 @@ func CalculateStatistics(people []*components.Human, smallCity, largeCity *compo
 			}
 		}
 
-		// Статистика дружбы и местоположения (только для живых)
+		// Статистика дружбы, здоровья и местоположения (только для живых)
 		if !person.Dead {
+			stats.AverageHealth += person.Health
+			if person.IsSick() {
+				stats.SickCount++
+			}
+			if person.Hospital != nil {
+				stats.HospitalizedCount++
+			}
+
 			stats.TotalFriends += len(person.Friends)
 			if len(person.Friends) > 0 {
 				stats.PeopleWithFriends++
@@ -100,12 +145,36 @@ func CalculateStatistics(people []*components.Human, smallCity, largeCity *compo
 		}
 	}
 
+	// Статистика домохозяйств
+	for _, household := range components.Households(people) {
+		stats.HouseholdCount++
+		stats.AverageHouseholdSize += float64(len(household.Members))
+		stats.HouseholdBudget += household.Budget
+		stats.HouseholdIncome += household.Income
+		stats.HouseholdExpenses += household.Expenses
+		for member := range household.Members {
+			if member.Age < config.AdultAge {
+				stats.FamilyHouseholds++
+				break
+			}
+		}
+	}
+	stats.TotalMoney += stats.HouseholdBudget
+	if stats.HouseholdCount > 0 {
+		stats.AverageHouseholdSize /= float64(stats.HouseholdCount)
+	}
+
+	stats.DivorceCount /= 2 // Каждый развод учтен у обоих супругов
+	if stats.AliveCount > 0 {
+		stats.AverageHealth /= float64(stats.AliveCount)
+	}
+
 	return stats
 }
 
 // PrintSimulationSummary выводит сводную статистику симуляции
-func PrintSimulationSummary(people []*components.Human, smallCity, largeCity *components.Location) {
-	stats := CalculateStatistics(people, smallCity, largeCity)
+func PrintSimulationSummary(people []*components.Human, cities []*components.Location) {
+	stats := CalculateStatistics(people, cities)
 
 	fmt.Println("========================================")
 	fmt.Println("SIMULATION SUMMARY")
@@ -115,10 +184,21 @@ func PrintSimulationSummary(people []*components.Human, smallCity, largeCity *co
 		len(people), stats.MaleCount, stats.FemaleCount)
 	fmt.Printf("Survival Rate: %d/%d humans alive (%.1f%%)\n",
 		stats.AliveCount, len(people), float64(stats.AliveCount)/float64(len(people))*100)
+	if len(stats.DeathCauses) > 0 {
+		fmt.Printf("Deaths by cause:")
+		for _, cause := range components.DeathCauses {
+			fmt.Printf(" %s %d", cause, stats.DeathCauses[string(cause)])
+		}
+		fmt.Println()
+	}
+	fmt.Printf("Health: average %.2f, %d sick, %d in hospital\n",
+		stats.AverageHealth, stats.SickCount, stats.HospitalizedCount)
 	fmt.Printf("Employment Rate: %d/%d humans employed (%.1f%%)\n",
 		stats.EmployedCount, stats.AliveCount, float64(stats.EmployedCount)/float64(stats.AliveCount)*100)
 	fmt.Printf("Marriage Rate: %d/%d humans married (%.1f%%)\n",
 		stats.MarriedCount, stats.AliveCount, float64(stats.MarriedCount)/float64(stats.AliveCount)*100)
+	fmt.Printf("Divorces: %d total, %d humans currently divorced\n", stats.DivorceCount, stats.DivorcedCount)
+	fmt.Printf("Widowed: %d humans\n", stats.WidowedCount)
 	fmt.Printf("Children: %d children under 18 (%.1f%% of population)\n",
 		stats.ChildrenCount, float64(stats.ChildrenCount)/float64(len(people))*100)
 	fmt.Printf("Pregnancies: %d women currently pregnant\n", stats.PregnantCount)
@@ -126,10 +206,17 @@ func PrintSimulationSummary(people []*components.Human, smallCity, largeCity *co
 		stats.TotalChildren/2, float64(stats.TotalChildren)/float64(len(people)-stats.ChildrenCount)) // Divide by 2 since both parents count the same child
 	fmt.Printf("Marriage Moves: %d women moved to husband's building\n", stats.MoveCount)
 	fmt.Printf("People without housing: %d/%d (%.1f%%)\n",
-		stats.PeopleWithoutHousing, len(people), float64(stats.PeopleWithoutHousing)/float64(len(people))*100)
+		stats.PeopleWithoutHousing, stats.AliveCount, float64(stats.PeopleWithoutHousing)/float64(stats.AliveCount)*100)
 	fmt.Printf("Total Completed Global Targets: %d\n", stats.CompletedTargetsCount)
 	fmt.Printf("Average Completed Targets per Person: %.1f\n",
 		float64(stats.CompletedTargetsCount)/float64(len(people)))
+	fmt.Printf("Households: %d (average size %.1f, %d with children)\n",
+		stats.HouseholdCount, stats.AverageHouseholdSize, stats.FamilyHouseholds)
+	if stats.HouseholdCount > 0 {
+		fmt.Printf("Household Budgets: %d rubles total, last month average income %d, expenses %d rubles\n",
+			stats.HouseholdBudget, stats.HouseholdIncome/int64(stats.HouseholdCount),
+			stats.HouseholdExpenses/int64(stats.HouseholdCount))
+	}
 	fmt.Printf("Total Money in Economy: %d rubles\n", stats.TotalMoney)
 	fmt.Printf("Average Money per Person: %d rubles\n", stats.TotalMoney/int64(len(people)))
 	fmt.Printf("Total Items Acquired: %d\n", stats.TotalItems)
@@ -152,6 +239,12 @@ func PrintSimulationSummary(people []*components.Human, smallCity, largeCity *co
 		stats.AliveCount-stats.PeopleAtWork-stats.PeopleAtHome,
 		float64(stats.AliveCount-stats.PeopleAtWork-stats.PeopleAtHome)/float64(stats.AliveCount)*100)
 
+	fmt.Printf("City Residents:\n")
+	for _, city := range cities {
+		fmt.Printf("  %s: %d (%.1f%%)\n", city.Name, stats.CityResidents[city.Name],
+			float64(stats.CityResidents[city.Name])/float64(stats.AliveCount)*100)
+	}
+
 	// Статистика выполнения целей
 	fmt.Println("\nTarget Completion Statistics:")
 	for targetName, count := range stats.TargetStats {
@@ -8,12 +8,79 @@ import (
 
 // Random обеспечивает потокобезопасную генерацию случайных чисел
 type Random struct {
-	mu   sync.Mutex
-	rand *rand.Rand
+	mu     sync.Mutex
+	rand   *rand.Rand
+	source *splitMixSource
+	seed   int64
 }
 
-var GlobalRandom = &Random{
-	rand: rand.New(rand.NewSource(time.Now().UnixNano())),
+// splitMixSource - источник SplitMix64. В отличие от стандартного источника
+// math/rand его состояние - одно число, которое можно сохранить и восстановить
+type splitMixSource struct {
+	state uint64
+}
+
+func (s *splitMixSource) Seed(seed int64) {
+	s.state = uint64(seed)
+}
+
+func (s *splitMixSource) Uint64() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	return mix64(s.state)
+}
+
+func (s *splitMixSource) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+var GlobalRandom = NewRandom(time.Now().UnixNano())
+
+// NewRandom создает генератор случайных чисел с заданным зерном
+func NewRandom(seed int64) *Random {
+	source := &splitMixSource{state: uint64(seed)}
+	return &Random{
+		rand:   rand.New(source),
+		source: source,
+		seed:   seed,
+	}
+}
+
+// SetGlobalSeed пересоздает GlobalRandom с заданным зерном
+func SetGlobalSeed(seed int64) {
+	GlobalRandom = NewRandom(seed)
+}
+
+// Seed возвращает зерно, с которым был создан генератор
+func (r *Random) Seed() int64 {
+	return r.seed
+}
+
+// State возвращает текущее состояние генератора (для сохранения контрольной точки)
+func (r *Random) State() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.source.state
+}
+
+// SetState восстанавливает состояние генератора, сохраненное через State
+func (r *Random) SetState(state uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.source.state = state
+}
+
+// Derive создает независимый поток случайных чисел для идентификатора id.
+// Поток зависит только от зерна генератора и id, но не от его текущего состояния,
+// поэтому порядок вызовов Derive не влияет на результат
+func (r *Random) Derive(id int) *Random {
+	return NewRandom(int64(mix64(uint64(r.seed) ^ mix64(uint64(id)+1))))
+}
+
+// mix64 перемешивает биты числа (финализатор SplitMix64)
+func mix64(x uint64) uint64 {
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
 }
 
 // Next возвращает случайное uint64
@@ -6,6 +6,8 @@ import (
 	"os"
 	"strconv"
 	"strings"
+
+	"github.com/fallra1n/humanity/src/config"
 )
 
 // Tick представляет глобальный счетчик времени
@@ -23,6 +25,11 @@ func (t *Tick) Increment() {
 	t.tick++
 }
 
+// Set устанавливает значение счетчика (при восстановлении из контрольной точки)
+func (t *Tick) Set(tick uint64) {
+	t.tick = tick
+}
+
 // IsNatural проверяет, представляет ли строка натуральное число
 func IsNatural(s string) bool {
 	if _, err := strconv.Atoi(s); err != nil {
@@ -111,18 +118,38 @@ func Compare(a int64, op string, b int64) bool {
 	}
 }
 
+// Sequence - непустая строка конфигурационного файла, разбитая на слова
+type Sequence struct {
+	Line  int // номер строки в файле (с 1)
+	Words []string
+}
+
 // LoadSequencesFromFile загружает конфигурацию из файла
 func LoadSequencesFromFile(filename string) ([][]string, error) {
+	sequences, err := LoadNumberedSequencesFromFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([][]string, 0, len(sequences))
+	for _, sequence := range sequences {
+		result = append(result, sequence.Words)
+	}
+	return result, nil
+}
+
+// LoadNumberedSequencesFromFile загружает конфигурацию из файла вместе с номерами строк
+func LoadNumberedSequencesFromFile(filename string) ([]Sequence, error) {
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file %s: %v", filename, err)
 	}
 	defer file.Close()
 
-	var result [][]string
+	var result []Sequence
 	scanner := bufio.NewScanner(file)
 
-	for scanner.Scan() {
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
 		line := strings.TrimSpace(scanner.Text())
 
 		// Пропустить пустые строки и комментарии
@@ -132,7 +159,7 @@ func LoadSequencesFromFile(filename string) ([][]string, error) {
 
 		words := Split(line, " ")
 		if len(words) > 0 {
-			result = append(result, words)
+			result = append(result, Sequence{Line: lineNumber, Words: words})
 		}
 	}
 
@@ -146,8 +173,11 @@ func LoadSequencesFromFile(filename string) ([][]string, error) {
 // IsSleepTime проверяет, находится ли текущий час в пределах времени сна (23:00 до 07:00)
 func IsSleepTime(currentHour uint64) bool {
 	hourOfDay := currentHour % 24
-	// Сон с 23:00 до 07:00
-	return hourOfDay >= 23 || hourOfDay < 7
+	// Сон с 23:00 до 07:00 (интервал может переходить через полночь)
+	if config.SleepStartHour > config.SleepEndHour {
+		return hourOfDay >= config.SleepStartHour || hourOfDay < config.SleepEndHour
+	}
+	return hourOfDay >= config.SleepStartHour && hourOfDay < config.SleepEndHour
 }
 
 // GetHourOfDay возвращает час дня (0-23) из глобального времени
@@ -159,7 +189,7 @@ func GetHourOfDay(globalHour uint64) uint64 {
 func IsWorkTime(currentHour uint64) bool {
 	hourOfDay := currentHour % 24
 	// Работа с 09:00 до 18:00
-	return hourOfDay >= 9 && hourOfDay < 18
+	return hourOfDay >= config.WorkStartHour && hourOfDay < config.WorkEndHour
 }
 
 // IsWorkDay проверяет, является ли это рабочим днем (понедельник-пятница)
@@ -0,0 +1,349 @@
+package src
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Минимальная реализация WebSocket (RFC 6455) для трансляции положения людей.
+// Сервер только отправляет текстовые кадры; от клиента принимаются ping и close,
+// остальные кадры читаются и отбрасываются
+
+// wsGUID - константа из RFC 6455 для вычисления Sec-WebSocket-Accept
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Коды операций кадров WebSocket
+const (
+	wsOpText  byte = 0x1
+	wsOpClose byte = 0x8
+	wsOpPing  byte = 0x9
+	wsOpPong  byte = 0xA
+)
+
+const (
+	// Сколько кадров может ждать отправки одному клиенту. Если клиент не успевает,
+	// самый старый кадр выбрасывается, и цикл симуляции никогда не ждет клиента
+	wsClientBuffer = 4
+
+	// Сколько ждать записи кадра, прежде чем отключить клиента
+	wsWriteTimeout = 10 * time.Second
+
+	// Максимальный размер кадра от клиента
+	wsMaxClientFrame = 64 * 1024
+)
+
+// agentPosition - положение и состояние человека в кадре трансляции
+type agentPosition struct {
+	ID            int      `json:"id"`
+	Alive         bool     `json:"alive"`
+	Lat           *float64 `json:"lat,omitempty"`
+	Lon           *float64 `json:"lon,omitempty"`
+	Building      string   `json:"building"`
+	BuildingType  string   `json:"building_type"`
+	JobStatus     string   `json:"job_status"`
+	MaritalStatus string   `json:"marital_status"`
+}
+
+// positionFrame - кадр трансляции: положение всех людей на конец часа
+type positionFrame struct {
+	Tick   uint64          `json:"tick"`
+	Agents []agentPosition `json:"agents"`
+}
+
+// PositionStream транслирует положение людей всем подключенным клиентам WebSocket
+type PositionStream struct {
+	mu      sync.Mutex
+	clients map[*wsClient]bool
+}
+
+// wsClient - одно подключение WebSocket
+type wsClient struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	send    chan []byte
+	done    chan struct{}
+	writeMu sync.Mutex
+	once    sync.Once
+}
+
+// NewPositionStream создает трансляцию без клиентов
+func NewPositionStream() *PositionStream {
+	return &PositionStream{clients: make(map[*wsClient]bool)}
+}
+
+// ServeHTTP принимает подключение WebSocket
+func (p *PositionStream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet ||
+		!headerContains(r.Header, "Connection", "upgrade") ||
+		!headerContains(r.Header, "Upgrade", "websocket") {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("websocket upgrade required"))
+		return
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		writeError(w, http.StatusUpgradeRequired, fmt.Errorf("unsupported websocket version"))
+		return
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("missing Sec-WebSocket-Key"))
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("connection does not support hijacking"))
+		return
+	}
+	conn, buffered, err := hijacker.Hijack()
+	if err != nil {
+		log.Printf("Warning: WebSocket hijack failed: %v", err)
+		return
+	}
+
+	accept := sha1.Sum([]byte(key + wsGUID))
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(accept[:]) + "\r\n\r\n"
+	conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	if _, err := conn.Write([]byte(response)); err != nil {
+		conn.Close()
+		return
+	}
+
+	client := &wsClient{
+		conn:   conn,
+		reader: buffered.Reader,
+		send:   make(chan []byte, wsClientBuffer),
+		done:   make(chan struct{}),
+	}
+
+	p.mu.Lock()
+	p.clients[client] = true
+	p.mu.Unlock()
+
+	go client.writeLoop()
+	go func() {
+		client.readLoop()
+		p.remove(client)
+	}()
+}
+
+// HasClients сообщает, есть ли подключенные клиенты (чтобы не собирать кадр впустую)
+func (p *PositionStream) HasClients() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.clients) > 0
+}
+
+// Broadcast ставит кадр в очередь каждому клиенту, не блокируясь.
+// Если очередь клиента заполнена, самый старый кадр заменяется новым
+func (p *PositionStream) Broadcast(frame []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for client := range p.clients {
+		select {
+		case client.send <- frame:
+			continue
+		default:
+		}
+
+		select {
+		case <-client.send:
+		default:
+		}
+		select {
+		case client.send <- frame:
+		default:
+		}
+	}
+}
+
+// Close отключает всех клиентов
+func (p *PositionStream) Close() {
+	p.mu.Lock()
+	clients := p.clients
+	p.clients = make(map[*wsClient]bool)
+	p.mu.Unlock()
+
+	for client := range clients {
+		client.writeFrame(wsOpClose, nil)
+		client.close()
+	}
+}
+
+func (p *PositionStream) remove(client *wsClient) {
+	p.mu.Lock()
+	delete(p.clients, client)
+	p.mu.Unlock()
+	client.close()
+}
+
+// writeLoop отправляет кадры из очереди клиента
+func (c *wsClient) writeLoop() {
+	for {
+		select {
+		case frame := <-c.send:
+			if err := c.writeFrame(wsOpText, frame); err != nil {
+				c.close()
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// readLoop читает кадры клиента до закрытия соединения
+func (c *wsClient) readLoop() {
+	for {
+		opcode, payload, err := c.readFrame()
+		if err != nil {
+			return
+		}
+
+		switch opcode {
+		case wsOpClose:
+			c.writeFrame(wsOpClose, nil)
+			return
+		case wsOpPing:
+			if err := c.writeFrame(wsOpPong, payload); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readFrame читает один кадр клиента (кадры клиента всегда замаскированы)
+func (c *wsClient) readFrame() (byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return 0, nil, err
+	}
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+
+	if !masked {
+		return 0, nil, errors.New("unmasked client frame")
+	}
+	if length > wsMaxClientFrame {
+		return 0, nil, fmt.Errorf("client frame too large: %d bytes", length)
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+		return 0, nil, err
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return opcode, payload, nil
+}
+
+// writeFrame отправляет один незамаскированный кадр
+func (c *wsClient) writeFrame(opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+	switch length := len(payload); {
+	case length < 126:
+		header = append(header, byte(length))
+	case length <= 0xFFFF:
+		header = append(header, 126)
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header = append(header, 127)
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	buffers := net.Buffers{header, payload}
+	_, err := buffers.WriteTo(c.conn)
+	return err
+}
+
+func (c *wsClient) close() {
+	c.once.Do(func() {
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+// headerContains проверяет, содержит ли заголовок значение из списка через запятую (без учета регистра)
+func headerContains(header http.Header, name, value string) bool {
+	for _, field := range header.Values(name) {
+		for _, part := range strings.Split(field, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// streamPositions отправляет положение всех людей подключенным клиентам
+func (s *Simulation) streamPositions(hour uint64) {
+	if s.stream == nil || !s.stream.HasClients() {
+		return
+	}
+
+	records := snapshotPeople(s.people, hour)
+	frame := positionFrame{Tick: hour, Agents: make([]agentPosition, 0, len(records))}
+	for _, record := range records {
+		position := agentPosition{
+			ID:            record.AgentID,
+			Alive:         record.Alive,
+			Building:      record.Location,
+			BuildingType:  record.BuildingType,
+			JobStatus:     record.JobStatus,
+			MaritalStatus: record.MaritalStatus,
+		}
+		if record.HasGeo {
+			lat, lon := record.Lat, record.Lon
+			position.Lat, position.Lon = &lat, &lon
+		}
+		frame.Agents = append(frame.Agents, position)
+	}
+
+	data, err := json.Marshal(frame)
+	if err != nil {
+		log.Printf("Warning: Failed to encode positions: %v", err)
+		return
+	}
+	s.stream.Broadcast(data)
+}